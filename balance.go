@@ -0,0 +1,162 @@
+package stripe
+
+import (
+	"context"
+	"net/url"
+)
+
+// Balance Transaction Types
+const (
+	TxTypeCharge               = "charge"
+	TxTypeRefund               = "refund"
+	TxTypeAdjustment           = "adjustment"
+	TxTypeApplicationFee       = "application_fee"
+	TxTypeApplicationFeeRefund = "application_fee_refund"
+	TxTypeTransfer             = "transfer"
+	TxTypeTransferCancel       = "transfer_cancel"
+	TxTypeTransferFailure      = "transfer_failure"
+	TxTypeStripeFee            = "stripe_fee"
+)
+
+// Balance Transaction Statuses
+const (
+	TxStatusAvailable = "available"
+	TxStatusPending   = "pending"
+)
+
+// Balance represents the funds in your Stripe account, by currency. Funds
+// are pending until they become available, and can then be transferred.
+//
+// see https://stripe.com/docs/api#balance_object
+type Balance struct {
+	Available []*BalanceAmount `json:"available"`
+	Pending   []*BalanceAmount `json:"pending"`
+	Livemode  bool             `json:"livemode"`
+}
+
+// BalanceAmount is the part of a Balance in a single currency.
+type BalanceAmount struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// BalanceTransaction represents a single movement of funds in your Stripe
+// balance, ie the proceeds of a Charge or the withdrawal of a disputed
+// amount.
+//
+// see https://stripe.com/docs/api#balance_transaction_object
+type BalanceTransaction struct {
+	Id          string        `json:"id"`
+	Amount      int64         `json:"amount"`
+	Currency    string        `json:"currency"`
+	Net         int64         `json:"net"`
+	Fee         int64         `json:"fee"`
+	Details     []*FeeDetails `json:"fee_details"`
+	Type        string        `json:"type"`
+	Status      string        `json:"status"`
+	Desc        String        `json:"description"`
+	Source      String        `json:"source"`
+	Created     int64         `json:"created"`
+	AvailableOn int64         `json:"available_on"`
+}
+
+// BalanceHistoryParams encapsulates options for listing Balance
+// Transactions.
+type BalanceHistoryParams struct {
+	ListParams
+
+	// (Optional) Only return transactions that become available within the
+	// given range.
+	AvailableOn *RangeQuery `form:"available_on,omitempty"`
+
+	// (Optional) Only return transactions in this currency.
+	Currency string `form:"currency,omitempty"`
+
+	// (Optional) Only return transactions of this type, one of the Balance
+	// Transaction Types.
+	Type string `form:"type,omitempty"`
+
+	// (Optional) Only return transactions created by the object with this
+	// ID, ie a Charge.
+	Source string `form:"source,omitempty"`
+
+	// (Optional) Only return transactions paid out by the transfer with this
+	// ID.
+	Transfer string `form:"transfer,omitempty"`
+}
+
+// BalanceClient encapsulates operations for querying your balance and its
+// history using the Stripe REST API.
+type BalanceClient struct {
+	client *Client
+}
+
+// Retrieves the current Balance of your account.
+//
+// see https://stripe.com/docs/api#retrieve_balance
+func (self *BalanceClient) Retrieve(opts ...RequestOption) (*Balance, error) {
+	return self.RetrieveContext(context.Background(), opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *BalanceClient) RetrieveContext(ctx context.Context, opts ...RequestOption) (*Balance, error) {
+	balance := Balance{}
+	err := self.client.query(ctx, "GET", "/v1/balance", nil, &balance, opts...)
+	return &balance, err
+}
+
+// Retrieves the Balance Transaction with the given ID.
+//
+// see https://stripe.com/docs/api#retrieve_balance_transaction
+func (self *BalanceClient) RetrieveTransaction(id string, opts ...RequestOption) (*BalanceTransaction, error) {
+	return self.RetrieveTransactionContext(context.Background(), id, opts...)
+}
+
+// RetrieveTransactionContext is the context-aware version of
+// RetrieveTransaction.
+func (self *BalanceClient) RetrieveTransactionContext(ctx context.Context, id string, opts ...RequestOption) (*BalanceTransaction, error) {
+	tx := BalanceTransaction{}
+	path := "/v1/balance/history/" + url.QueryEscape(id)
+	err := self.client.query(ctx, "GET", path, nil, &tx, opts...)
+	return &tx, err
+}
+
+// Page returns a single page of your Balance Transactions (the balance
+// history), filtered by the given params. The returned List reports whether
+// more Balance Transactions are available.
+//
+// see https://stripe.com/docs/api#balance_history
+func (self *BalanceClient) Page(params *BalanceHistoryParams, opts ...RequestOption) (*List[*BalanceTransaction], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *BalanceClient) PageContext(ctx context.Context, params *BalanceHistoryParams, opts ...RequestOption) (*List[*BalanceTransaction], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all of your Balance Transactions (the balance
+// history), filtered by the given params, which fetches them from Stripe one
+// page at a time.
+//
+// see https://stripe.com/docs/api#balance_history
+func (self *BalanceClient) Iter(params *BalanceHistoryParams, opts ...RequestOption) *Iter[*BalanceTransaction] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *BalanceClient) IterContext(ctx context.Context, params *BalanceHistoryParams, opts ...RequestOption) *Iter[*BalanceTransaction] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*BalanceTransaction], error) {
+		return self.page(ctx, values, opts...)
+	}, func(tx *BalanceTransaction) string { return tx.Id })
+}
+
+func (self *BalanceClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*BalanceTransaction], error) {
+	list := List[*BalanceTransaction]{}
+	err := self.client.query(ctx, "GET", "/v1/balance/history", values, &list, opts...)
+	return &list, err
+}
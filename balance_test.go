@@ -0,0 +1,82 @@
+package stripe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRetrieveBalance will test that the available and pending funds are
+// decoded by currency.
+func TestRetrieveBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/balance" {
+			t.Errorf("Expected path /v1/balance, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"object":"balance","available":[{"amount":12500,"currency":"usd"}],` +
+			`"pending":[{"amount":400,"currency":"usd"},{"amount":300,"currency":"eur"}]}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	balance, err := client.Balances.Retrieve()
+	if err != nil {
+		t.Errorf("Expected Balance, got Error %s", err.Error())
+		return
+	}
+	if len(balance.Available) != 1 || balance.Available[0].Amount != 12500 {
+		t.Errorf("Expected 12500 available, got %v", balance.Available)
+	}
+	if len(balance.Pending) != 2 || balance.Pending[1].Currency != EUR {
+		t.Errorf("Expected pending usd and eur, got %v", balance.Pending)
+	}
+}
+
+// TestBalanceHistory will test that the balance history is filtered, and
+// paged through using the starting_after cursor.
+func TestBalanceHistory(t *testing.T) {
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		if r.FormValue("starting_after") == "" {
+			w.Write([]byte(`{"object":"list","has_more":true,"data":[` +
+				`{"id":"txn_2","amount":400,"fee":42,"net":358,"type":"charge","source":"ch_1","available_on":1396310400}]}`))
+			return
+		}
+		w.Write([]byte(`{"object":"list","has_more":false,"data":[` +
+			`{"id":"txn_1","amount":-100,"fee":0,"net":-100,"type":"refund","source":"re_1","available_on":1396310400}]}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := BalanceHistoryParams{
+		ListParams:  ListParams{Limit: 1},
+		AvailableOn: &RangeQuery{Gte: 1393632000, Lt: 1396396800},
+		Currency:    USD,
+	}
+	net := int64(0)
+	iter := client.Balances.Iter(&params)
+	for iter.Next() {
+		net += iter.Current().Net
+	}
+	if err := iter.Err(); err != nil {
+		t.Errorf("Expected Balance Transactions, got Error %s", err.Error())
+	}
+	if net != 258 {
+		t.Errorf("Expected net 258, got %d", net)
+	}
+
+	want := []string{
+		"/v1/balance/history?available_on%5Bgte%5D=1393632000&available_on%5Blt%5D=1396396800&currency=usd&limit=1",
+		"/v1/balance/history?available_on%5Bgte%5D=1393632000&available_on%5Blt%5D=1396396800&currency=usd&limit=1&starting_after=txn_2",
+	}
+	for i := range want {
+		if i >= len(queries) || queries[i] != want[i] {
+			t.Errorf("Expected request %s, got %v", want[i], queries)
+		}
+	}
+}
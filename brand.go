@@ -0,0 +1,132 @@
+package stripe
+
+// Credit Card Types recognized by GetCardType, in addition to the types
+// accepted by the Stripe API.
+const (
+	Maestro  = "Maestro"
+	Mir      = "Mir"
+	UnionPay = "UnionPay"
+)
+
+// CardBrand describes a Credit Card brand (ie Visa, Discover), and the format
+// of its card numbers.
+type CardBrand struct {
+	// The name of the brand, one of the Credit Card Type constants.
+	Name string
+
+	// The valid lengths of a card number.
+	Lengths []int
+
+	// The length of the card security code.
+	CVCLength int
+
+	// The size of each group of digits when a card number is displayed,
+	// ie 4, 6 and 5 for 3782 822463 10005.
+	Spacing []int
+}
+
+// IsValidLength returns true if a card number of the given length is valid
+// for the brand.
+func (self CardBrand) IsValidLength(n int) bool {
+	for _, length := range self.Lengths {
+		if n == length {
+			return true
+		}
+	}
+	return false
+}
+
+// Credit Card brand descriptors.
+var (
+	brandAmericanExpress = &CardBrand{AmericanExpress, []int{15}, 4, []int{4, 6, 5}}
+	brandDinersClub      = &CardBrand{DinersClub, []int{14, 15, 16, 17, 18, 19}, 3, []int{4, 6, 4, 5}}
+	brandDiscover        = &CardBrand{Discover, []int{16, 17, 18, 19}, 3, []int{4, 4, 4, 4, 3}}
+	brandJCB             = &CardBrand{JCB, []int{15, 16, 17, 18, 19}, 3, []int{4, 4, 4, 4, 3}}
+	brandMaestro         = &CardBrand{Maestro, []int{12, 13, 14, 15, 16, 17, 18, 19}, 3, []int{4, 4, 4, 4, 3}}
+	brandMasterCard      = &CardBrand{MasterCard, []int{16}, 3, []int{4, 4, 4, 4}}
+	brandMir             = &CardBrand{Mir, []int{16, 17, 18, 19}, 3, []int{4, 4, 4, 4, 3}}
+	brandUnionPay        = &CardBrand{UnionPay, []int{16, 17, 18, 19}, 3, []int{4, 4, 4, 4, 3}}
+	brandVisa            = &CardBrand{Visa, []int{13, 16, 19}, 3, []int{4, 4, 4, 4, 3}}
+	brandUnknown         = &CardBrand{UnknownCard, []int{12, 13, 14, 15, 16, 17, 18, 19}, 3, []int{4, 4, 4, 4, 3}}
+)
+
+// binRange is an inclusive range of card number prefixes (Bank
+// Identification Numbers) belonging to a brand. Both ends of the range have
+// the same number of digits.
+type binRange struct {
+	lo, hi string
+	brand  *CardBrand
+}
+
+// binRanges is the table of known card number prefixes. When ranges
+// overlap, the range with the longest prefix wins, so more specific ranges
+// (ie Discover 622126-622925) can be listed alongside broader ones (ie
+// UnionPay 62).
+var binRanges = []binRange{
+	{"34", "34", brandAmericanExpress},
+	{"37", "37", brandAmericanExpress},
+
+	{"300", "305", brandDinersClub},
+	{"3095", "3095", brandDinersClub},
+	{"36", "36", brandDinersClub},
+	{"38", "39", brandDinersClub},
+
+	{"6011", "6011", brandDiscover},
+	{"622126", "622925", brandDiscover},
+	{"644", "649", brandDiscover},
+	{"65", "65", brandDiscover},
+
+	{"1800", "1800", brandJCB},
+	{"2131", "2131", brandJCB},
+	{"3528", "3589", brandJCB},
+
+	{"50", "50", brandMaestro},
+	{"56", "58", brandMaestro},
+	{"6304", "6304", brandMaestro},
+	{"67", "67", brandMaestro},
+
+	{"2221", "2720", brandMasterCard},
+	{"51", "55", brandMasterCard},
+
+	{"2200", "2204", brandMir},
+
+	{"62", "62", brandUnionPay},
+	{"81", "81", brandUnionPay},
+
+	{"4", "4", brandVisa},
+}
+
+// GetCardBrand determines the brand of a Credit Card based on the prefix of
+// its number, using the longest matching prefix. Spaces and dashes are
+// ignored. If the prefix is not recognized, or the number contains anything
+// other than digits, the Unknown brand is returned.
+//
+// The returned CardBrand is a copy, which the caller is free to modify.
+func GetCardBrand(card string) CardBrand {
+	card = NormalizeCardNumber(card)
+	if !isDigits(card) {
+		return brandUnknown.clone()
+	}
+
+	brand, longest := brandUnknown, 0
+	for _, r := range binRanges {
+		n := len(r.lo)
+		if n > len(card) || n <= longest {
+			continue
+		}
+		// prefixes of the same length compare like numbers
+		if prefix := card[:n]; prefix >= r.lo && prefix <= r.hi {
+			brand, longest = r.brand, n
+		}
+	}
+	return brand.clone()
+}
+
+// clone returns a copy of the brand, so that the descriptors of the binRanges
+// table can never be modified by a caller.
+func (self *CardBrand) clone() CardBrand {
+	brand := *self
+	brand.Lengths = append([]int(nil), self.Lengths...)
+	brand.Spacing = append([]int(nil), self.Spacing...)
+	return brand
+}
@@ -0,0 +1,75 @@
+package stripe
+
+import (
+	"testing"
+)
+
+// TestGetCardBrand will test that the longest matching prefix determines the
+// brand, and that short or malformed numbers never panic.
+func TestGetCardBrand(t *testing.T) {
+	tests := []struct {
+		number string
+		brand  string
+	}{
+		{"", UnknownCard},
+		{"4", Visa},
+		{"37", AmericanExpress},
+		{"3", UnknownCard},
+		{"35", UnknownCard},
+		{"3528", JCB},
+		{"3590", UnknownCard},
+		{"2221", MasterCard},
+		{"2720", MasterCard},
+		{"2721", UnknownCard},
+		{"2200", Mir},
+		{"62", UnionPay},
+		{"622125", UnionPay},
+		{"622126", Discover},
+		{"622925", Discover},
+		{"644", Discover},
+		{"6759", Maestro},
+		{"6762", Maestro},
+		{"4242-4242", Visa},
+		{"42x2", UnknownCard},
+		{"abcd", UnknownCard},
+	}
+	for _, test := range tests {
+		if got := GetCardBrand(test.number).Name; got != test.brand {
+			t.Errorf("Expected brand %s for %q, got %s", test.brand, test.number, got)
+		}
+	}
+}
+
+// TestCardBrandDescriptor will test the lengths and CVC length reported by a
+// brand.
+func TestCardBrandDescriptor(t *testing.T) {
+	amex := GetCardBrand("378282246310005")
+	if amex.CVCLength != 4 {
+		t.Errorf("Expected American Express CVC length 4, got %d", amex.CVCLength)
+	}
+	if !amex.IsValidLength(15) || amex.IsValidLength(16) {
+		t.Errorf("Expected American Express length 15, got %v", amex.Lengths)
+	}
+
+	params := CardParams{Number: "42424242424242", ExpMonth: 12, ExpYear: 2099}
+	if err := params.Validate(); err == nil {
+		t.Errorf("Expected 14 digit Visa number to be invalid")
+	}
+}
+
+// TestGetCardBrandCopy will test that modifying a returned brand does not
+// change the brand detected for other callers.
+func TestGetCardBrandCopy(t *testing.T) {
+	visa := GetCardBrand("4242424242424242")
+	visa.Name = "Modified"
+	visa.Lengths[0] = 1
+	visa.Spacing[0] = 1
+
+	visa = GetCardBrand("4242424242424242")
+	if visa.Name != Visa || visa.Lengths[0] != 13 || visa.Spacing[0] != 4 {
+		t.Errorf("Expected unmodified Visa brand, got %+v", visa)
+	}
+	if got := FormatCardNumber("4242424242424242"); got != "4242 4242 4242 4242" {
+		t.Errorf("Expected formatted 4242 4242 4242 4242, got %s", got)
+	}
+}
@@ -1,9 +1,12 @@
 package stripe
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Credit Card Types accepted by the Stripe API.
@@ -36,62 +39,265 @@ type Card struct {
 	AddressLine1Check String `json:"address_line1_check,omitempty"`
 	AddressZipCheck   String `json:"address_zip_check,omitempty"`
 	CVCCheck          String `json:"cvc_check,omitempty"`
+	Customer          String `json:"customer,omitempty"`
 }
 
 // CardParams encapsulates options for Creating or Updating Credit Cards.
 type CardParams struct {
 	// (Optional) Cardholder's full name.
-	Name string
+	Name string `form:"name,omitempty"`
 
 	// The card number, as a string without any separators.
-	Number string
+	Number string `form:"number"`
 
 	// Two digit number representing the card's expiration month.
-	ExpMonth int
+	ExpMonth int `form:"exp_month"`
 
 	// Four digit number representing the card's expiration year.
-	ExpYear int
+	ExpYear int `form:"exp_year"`
 
 	// Card security code
-	CVC string
+	CVC string `form:"cvc,omitempty"`
 
 	// (Optional) Billing address line 1
-	Address1 string
+	Address1 string `form:"address_line1,omitempty"`
 
 	// (Optional) Billing address line 2
-	Address2 string
+	Address2 string `form:"address_line2,omitempty"`
 
 	// (Optional) Billing address country
-	AddressCountry string
+	AddressCountry string `form:"address_country,omitempty"`
 
 	// (Optional) Billing address state
-	AddressState string
+	AddressState string `form:"address_state,omitempty"`
 
 	// (Optional) Billing address zip code
-	AddressZip string
+	AddressZip string `form:"address_zip,omitempty"`
+}
+
+// Validate checks the card number checksum, expiration date and security
+// code, without making a request, and returns a ValidationError listing every
+// invalid parameter.
+func (self *CardParams) Validate() error {
+	errs := fieldErrors{}
+	if len(self.Number) < 12 || len(self.Number) > 19 || !isDigits(self.Number) {
+		errs.add("number", "must be 12 to 19 digits")
+	} else if valid, _ := IsLuhnValid(self.Number); !valid {
+		errs.add("number", "is not a valid card number")
+	} else if brand := GetCardBrand(self.Number); !brand.IsValidLength(len(self.Number)) {
+		errs.add("number", "is not a valid "+brand.Name+" card number length")
+	}
+
+	// a card expires at the end of its expiration month. Stripe accepts both
+	// two and four digit years.
+	year := self.ExpYear
+	if year < 100 {
+		year += 2000
+	}
+	now := time.Now()
+	switch {
+	case self.ExpMonth < 1 || self.ExpMonth > 12:
+		errs.add("exp_month", "must be between 1 and 12")
+	case year < now.Year() || (year == now.Year() && self.ExpMonth < int(now.Month())):
+		errs.add("exp_year", "is in the past, the card has expired")
+	}
+
+	if self.CVC != "" && (len(self.CVC) < 3 || len(self.CVC) > 4 || !isDigits(self.CVC)) {
+		errs.add("cvc", "must be 3 or 4 digits")
+	}
+	return errs.err()
+}
+
+// String returns the card params with the card number masked and the
+// security code hidden, so that printing them (ie with %v or %+v) never leaks
+// the card details. It has a value receiver, so that printing a CardParams
+// value is masked too.
+func (self CardParams) String() string {
+	return fmt.Sprintf("%+v", self.masked())
+}
+
+// GoString returns the card params, as printed with %#v, with the card number
+// masked and the security code hidden.
+func (self CardParams) GoString() string {
+	return strings.Replace(fmt.Sprintf("%#v", self.masked()), "stripe.cardParams", "stripe.CardParams", 1)
+}
+
+// cardParams has the fields of CardParams, but none of its methods, so it
+// can be printed without recursing into String and GoString.
+type cardParams CardParams
+
+func (self CardParams) masked() cardParams {
+	masked := cardParams(self)
+	masked.Number = MaskCardNumber(masked.Number)
+	if masked.CVC != "" {
+		masked.CVC = "***"
+	}
+	return masked
+}
+
+// CardUpdateParams encapsulates options for updating a Credit Card. The card
+// number and security code of an existing card cannot be changed.
+type CardUpdateParams struct {
+	// (Optional) Cardholder's full name.
+	Name Optional[string] `form:"name"`
+
+	// (Optional) Two digit number representing the card's expiration month.
+	ExpMonth int `form:"exp_month,omitempty"`
+
+	// (Optional) Four digit number representing the card's expiration year.
+	ExpYear int `form:"exp_year,omitempty"`
+
+	// (Optional) Billing address line 1
+	Address1 Optional[string] `form:"address_line1"`
+
+	// (Optional) Billing address line 2
+	Address2 Optional[string] `form:"address_line2"`
+
+	// (Optional) Billing address city
+	AddressCity Optional[string] `form:"address_city"`
+
+	// (Optional) Billing address country
+	AddressCountry Optional[string] `form:"address_country"`
+
+	// (Optional) Billing address state
+	AddressState Optional[string] `form:"address_state"`
+
+	// (Optional) Billing address zip code
+	AddressZip Optional[string] `form:"address_zip"`
 }
 
 // CardClient encapsulates operations for creating, updating, deleting and
 // querying cards using the Stripe REST API.
-type CardClient struct{}
+type CardClient struct {
+	client *Client
+}
 
-func (self *CardClient) Create(c *CardParams, customerId string) (*Card, error) {
+// Creates a new Card for the Customer with the given ID.
+//
+// see https://stripe.com/docs/api#create_card
+func (self *CardClient) Create(c *CardParams, customerId string, opts ...RequestOption) (*Card, error) {
+	return self.CreateContext(context.Background(), c, customerId, opts...)
+}
+
+// CreateContext is the context-aware version of Create.
+func (self *CardClient) CreateContext(ctx context.Context, c *CardParams, customerId string, opts ...RequestOption) (*Card, error) {
 	card := Card{}
+	if err := self.client.validate(c); err != nil {
+		return &card, err
+	}
 	values := url.Values{}
-	appendCardParamsToValues(c, &values)
+	appendNestedParamsToValues("card", c, &values)
 
-	err := query("POST", "/v1/customers/"+customerId+"/cards", values, &card)
+	err := self.client.query(ctx, "POST", "/v1/customers/"+customerId+"/cards", values, &card, opts...)
+	return &card, err
+}
+
+// Retrieves the Card with the given ID, belonging to the Customer with the
+// given ID.
+//
+// see https://stripe.com/docs/api#retrieve_card
+func (self *CardClient) Retrieve(cardId string, customerId string, opts ...RequestOption) (*Card, error) {
+	return self.RetrieveContext(context.Background(), cardId, customerId, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *CardClient) RetrieveContext(ctx context.Context, cardId string, customerId string, opts ...RequestOption) (*Card, error) {
+	card := Card{}
+	path := "/v1/customers/" + url.QueryEscape(customerId) + "/cards/" + url.QueryEscape(cardId)
+	err := self.client.query(ctx, "GET", path, nil, &card, opts...)
 	return &card, err
 }
 
-func (self *CardClient) Delete(cardId string, customerId string) (*DeleteResp, error) {
+// Updates the name, expiration date or billing address of the Card with the
+// given ID, belonging to the Customer with the given ID.
+//
+// see https://stripe.com/docs/api#update_card
+func (self *CardClient) Update(cardId string, customerId string, params *CardUpdateParams, opts ...RequestOption) (*Card, error) {
+	return self.UpdateContext(context.Background(), cardId, customerId, params, opts...)
+}
+
+// UpdateContext is the context-aware version of Update.
+func (self *CardClient) UpdateContext(ctx context.Context, cardId string, customerId string, params *CardUpdateParams, opts ...RequestOption) (*Card, error) {
+	card := Card{}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+
+	path := "/v1/customers/" + url.QueryEscape(customerId) + "/cards/" + url.QueryEscape(cardId)
+	err := self.client.query(ctx, "POST", path, values, &card, opts...)
+	return &card, err
+}
+
+// SetDefault makes the Card with the given ID the default card of the
+// Customer with the given ID, which is charged for subscriptions and for
+// charges that do not specify a card.
+//
+// see https://stripe.com/docs/api#update_customer
+func (self *CardClient) SetDefault(cardId string, customerId string, opts ...RequestOption) (*Customer, error) {
+	return self.SetDefaultContext(context.Background(), cardId, customerId, opts...)
+}
+
+// SetDefaultContext is the context-aware version of SetDefault.
+func (self *CardClient) SetDefaultContext(ctx context.Context, cardId string, customerId string, opts ...RequestOption) (*Customer, error) {
+	customers := CustomerClient{self.client}
+	return customers.UpdateContext(ctx, customerId, &CustomerParams{DefaultCard: cardId}, opts...)
+}
+
+// Deletes the Card with the given ID, belonging to the Customer with the
+// given ID.
+//
+// see https://stripe.com/docs/api#delete_card
+func (self *CardClient) Delete(cardId string, customerId string, opts ...RequestOption) (*DeleteResp, error) {
+	return self.DeleteContext(context.Background(), cardId, customerId, opts...)
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (self *CardClient) DeleteContext(ctx context.Context, cardId string, customerId string, opts ...RequestOption) (*DeleteResp, error) {
 	delResponse := DeleteResp{}
 	values := url.Values{}
 
-	err := query("DELETE", "/v1/customers/"+customerId+"/cards/"+cardId, values, &delResponse)
+	err := self.client.query(ctx, "DELETE", "/v1/customers/"+customerId+"/cards/"+cardId, values, &delResponse, opts...)
 	return &delResponse, err
 }
 
+// Page returns a single page of the Cards of the Customer with the given ID.
+// The returned List reports whether more Cards are available.
+//
+// see https://stripe.com/docs/api#list_cards
+func (self *CardClient) Page(customerId string, params *ListParams, opts ...RequestOption) (*List[*Card], error) {
+	return self.PageContext(context.Background(), customerId, params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *CardClient) PageContext(ctx context.Context, customerId string, params *ListParams, opts ...RequestOption) (*List[*Card], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, customerId, values, opts...)
+}
+
+// Iter returns an iterator over all of the Cards of the Customer with the
+// given ID, which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_cards
+func (self *CardClient) Iter(customerId string, params *ListParams, opts ...RequestOption) *Iter[*Card] {
+	return self.IterContext(context.Background(), customerId, params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *CardClient) IterContext(ctx context.Context, customerId string, params *ListParams, opts ...RequestOption) *Iter[*Card] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Card], error) {
+		return self.page(ctx, customerId, values, opts...)
+	}, func(c *Card) string { return c.Id })
+}
+
+func (self *CardClient) page(ctx context.Context, customerId string, values url.Values, opts ...RequestOption) (*List[*Card], error) {
+	list := List[*Card]{}
+	path := "/v1/customers/" + url.QueryEscape(customerId) + "/cards"
+	err := self.client.query(ctx, "GET", path, values, &list, opts...)
+	return &list, err
+}
+
 // IsLuhnValid uses the Luhn Algorithm (also known as the Mod 10 algorithm) to
 // verify a credit cards checksum, which helps flag accidental data entry
 // errors.
@@ -129,44 +335,50 @@ func IsLuhnValid(card string) (bool, error) {
 	return sum%10 == 0, nil
 }
 
-// GetCardType is a simple algorithm to determine the Card Type (ie Visa,
-// Discover) based on the Credit Card Number. If the Number is not recognized, a
-// value of "Unknown" will be returned.
-func GetCardType(card string) string {
-
-	switch card[0:1] {
-	case "4":
-		return Visa
-	case "2", "1":
-		switch card[0:4] {
-		case "2131", "1800":
-			return JCB
+// NormalizeCardNumber strips the spaces and dashes a user may enter in a
+// card number, ie "4242 4242-4242 4242" becomes "4242424242424242".
+func NormalizeCardNumber(card string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '\t', '\n', '\r':
+			return -1
 		}
-	case "6":
-		switch card[0:4] {
-		case "6011":
-			return Discover
-		}
-	case "5":
-		switch card[0:2] {
-		case "51", "52", "53", "54", "55":
-			return MasterCard
-		}
-	case "3":
-		switch card[0:2] {
-		case "34", "37":
-			return AmericanExpress
-		case "36":
-			return DinersClub
-		case "30":
-			switch card[0:3] {
-			case "300", "301", "302", "303", "304", "305":
-				return DinersClub
-			}
-		default:
-			return JCB
+		return r
+	}, card)
+}
+
+// FormatCardNumber normalizes a card number and groups its digits for
+// display, using the spacing of its brand, ie "3782 822463 10005" for an
+// American Express card and "4242 4242 4242 4242" for a Visa card.
+func FormatCardNumber(card string) string {
+	card = NormalizeCardNumber(card)
+	groups := []string{}
+	for _, size := range GetCardBrand(card).Spacing {
+		if len(card) <= size {
+			break
 		}
+		groups = append(groups, card[:size])
+		card = card[size:]
+	}
+	if card != "" {
+		groups = append(groups, card)
 	}
+	return strings.Join(groups, " ")
+}
 
-	return UnknownCard
+// MaskCardNumber normalizes a card number and masks all but its last four
+// digits, ie "************4242", so that it can be safely displayed or logged.
+func MaskCardNumber(card string) string {
+	card = NormalizeCardNumber(card)
+	if len(card) <= 4 {
+		return card
+	}
+	return strings.Repeat("*", len(card)-4) + card[len(card)-4:]
+}
+
+// GetCardType determines the Card Type (ie Visa, Discover) based on the
+// Credit Card Number. If the Number is not recognized, a value of "Unknown"
+// will be returned.
+func GetCardType(card string) string {
+	return GetCardBrand(card).Name
 }
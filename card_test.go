@@ -1,6 +1,10 @@
 package stripe
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -21,8 +25,13 @@ var cards = []*card{
 	&card{"361134239348202", DinersClub, false},      // should fail
 	&card{"300134239348202", DinersClub, false},      // should fail
 	&card{"521134239348202", MasterCard, false},      // should fail
-	&card{"380134239348202", JCB, false},             // should fail
+	&card{"380134239348202", DinersClub, false},      // should fail
 	&card{"180034239348202", JCB, false},             // should fail
+	&card{"2223003122003222", MasterCard, true},      // should pass
+	&card{"6011000990139424", Discover, true},        // should pass
+	&card{"6500000000000002", Discover, true},        // should pass
+	&card{"3566002020360505", JCB, true},             // should pass
+	&card{"6200000000000005", UnionPay, true},        // should pass
 }
 
 func TestLuhn(t *testing.T) {
@@ -38,3 +47,111 @@ func TestLuhn(t *testing.T) {
 		}
 	}
 }
+
+// TestUpdateCard will test that only the given card fields are sent, and
+// that a field set to null is cleared.
+func TestUpdateCard(t *testing.T) {
+	path, body := "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		path, body = r.URL.Path, r.PostForm.Encode()
+		w.Write([]byte(`{"id":"card_1","exp_year":2030}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := CardUpdateParams{
+		ExpYear:  2030,
+		Address2: Null[string](),
+	}
+	card, err := client.Cards.Update("card_1", "cus_1", &params)
+	if err != nil {
+		t.Errorf("Expected Card, got Error %s", err.Error())
+		return
+	}
+	if card.ExpYear != 2030 {
+		t.Errorf("Expected Card ExpYear 2030, got %d", card.ExpYear)
+	}
+	if path != "/v1/customers/cus_1/cards/card_1" {
+		t.Errorf("Expected path /v1/customers/cus_1/cards/card_1, got %s", path)
+	}
+	if body != "address_line2=&exp_year=2030" {
+		t.Errorf("Expected body address_line2=&exp_year=2030, got %s", body)
+	}
+}
+
+// TestSetDefaultCard will test that the default card of a customer is
+// switched, using the parameter name of the API version in use.
+func TestSetDefaultCard(t *testing.T) {
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		body = r.PostForm.Encode()
+		w.Write([]byte(`{"id":"cus_1","default_card":"card_2"}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	cust, err := client.Cards.SetDefault("card_2", "cus_1")
+	if err != nil {
+		t.Errorf("Expected Customer, got Error %s", err.Error())
+		return
+	}
+	if cust.DefaultCard.Id != "card_2" {
+		t.Errorf("Expected DefaultCard card_2, got %s", cust.DefaultCard.Id)
+	}
+	if body != "default_card=card_2" {
+		t.Errorf("Expected body default_card=card_2, got %s", body)
+	}
+
+	client.Cards.SetDefault("card_2", "cus_1", APIVersion("2015-02-18"))
+	if body != "default_source=card_2" {
+		t.Errorf("Expected body default_source=card_2, got %s", body)
+	}
+}
+
+// TestFormatCardNumber will test that card numbers are normalized, grouped
+// using the spacing of their brand, and masked.
+func TestFormatCardNumber(t *testing.T) {
+	tests := []struct {
+		input     string
+		formatted string
+		masked    string
+	}{
+		{"4242 4242-4242 4242", "4242 4242 4242 4242", "************4242"},
+		{"378282246310005", "3782 822463 10005", "***********0005"},
+		{"30569309025904", "3056 930902 5904", "**********5904"},
+		{"4242424242424242424", "4242 4242 4242 4242 424", "***************2424"},
+		{"424", "424", "424"},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		if got := FormatCardNumber(test.input); got != test.formatted {
+			t.Errorf("Expected formatted %q, got %q", test.formatted, got)
+		}
+		if got := MaskCardNumber(test.input); got != test.masked {
+			t.Errorf("Expected masked %q, got %q", test.masked, got)
+		}
+	}
+}
+
+// TestCardParamsString will test that printing card params never leaks the
+// card number or security code.
+func TestCardParamsString(t *testing.T) {
+	params := CardParams{Name: "George Costanza", Number: "4242424242424242", CVC: "123"}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		for _, value := range []interface{}{params, &params} {
+			got := fmt.Sprintf(format, value)
+			if strings.Contains(got, "4242424242424242") || strings.Contains(got, "123") {
+				t.Errorf("Expected masked card with %s, got %s", format, got)
+			}
+			if !strings.Contains(got, "************4242") {
+				t.Errorf("Expected last 4 digits with %s, got %s", format, got)
+			}
+		}
+	}
+}
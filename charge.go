@@ -1,8 +1,13 @@
 package stripe
 
 import (
+	"context"
+	"encoding/json"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // ISO 3-digit Currency Codes for major currencies (not the full list).
@@ -21,23 +26,67 @@ const (
 //
 // see https://stripe.com/docs/api#charge_object
 type Charge struct {
-	Id                   string        `json:"id"`
-	Desc                 String        `json:"description"`
-	Amount               int64         `json:"amount"`
-	Card                 *Card         `json:"card"`
-	Currency             string        `json:"currency"`
-	Created              int64         `json:"created"`
-	Customer             String        `json:"customer"`
-	Invoice              String        `json:"invoice"`
-	Fee                  int64         `json:"fee"`
-	Paid                 bool          `json:"paid"`
-	Details              []*FeeDetails `json:"fee_details"`
-	Refunded             bool          `json:"refunded"`
-	AmountRefunded       Int64         `json:"amount_refunded"`
-	FailureMessage       String        `json:"failure_message"`
-	Disputed             bool          `json:"disputed"`
-	Livemode             bool          `json:"livemode"`
-	StatementDescription string        `json:"statement_description"`
+	Id                   string                         `json:"id"`
+	Desc                 String                         `json:"description"`
+	Amount               int64                          `json:"amount"`
+	Card                 *Card                          `json:"card"`
+	Currency             string                         `json:"currency"`
+	Created              int64                          `json:"created"`
+	Customer             Expandable[Customer]           `json:"customer"`
+	Invoice              Expandable[Invoice]            `json:"invoice"`
+	Fee                  int64                          `json:"fee"`
+	BalanceTransaction   Expandable[BalanceTransaction] `json:"balance_transaction"`
+	Paid                 bool                           `json:"paid"`
+	Captured             bool                           `json:"captured"`
+	Details              []*FeeDetails                  `json:"fee_details"`
+	Refunded             bool                           `json:"refunded"`
+	AmountRefunded       Int64                          `json:"amount_refunded"`
+	Refunds              List[*Refund]                  `json:"refunds"`
+	FailureMessage       String                         `json:"failure_message"`
+	Disputed             bool                           `json:"disputed"`
+	Dispute              Expandable[Dispute]            `json:"dispute"`
+	Livemode             bool                           `json:"livemode"`
+	StatementDescription string                         `json:"statement_description"`
+	StatementDescriptor  string                         `json:"statement_descriptor"`
+	Source               *Card                          `json:"source"`
+	Metadata             map[string]string              `json:"metadata"`
+}
+
+func (self *Charge) UnmarshalJSON(data []byte) error {
+	type charge Charge
+	if err := json.Unmarshal(data, (*charge)(self)); err != nil {
+		return err
+	}
+
+	// newer API versions renamed statement_description and card, so
+	// we populate both fields regardless of the version used.
+	if self.StatementDescription == "" {
+		self.StatementDescription = self.StatementDescriptor
+	} else if self.StatementDescriptor == "" {
+		self.StatementDescriptor = self.StatementDescription
+	}
+	if self.Card == nil {
+		self.Card = self.Source
+	} else if self.Source == nil {
+		self.Source = self.Card
+	}
+	return nil
+}
+
+// AuthorizationExpiry is how long an uncaptured Charge can still be
+// captured. Once it expires, the Charge is refunded and capturing it fails
+// with ErrChargeExpiredForCapture.
+//
+// see https://stripe.com/docs/charges#auth-and-capture
+const AuthorizationExpiry = 7 * 24 * time.Hour
+
+// CaptureBy returns the time at which an uncaptured Charge expires, or the
+// zero time if the Charge was already captured.
+func (self *Charge) CaptureBy() time.Time {
+	if self.Captured {
+		return time.Time{}
+	}
+	return time.Unix(self.Created, 0).Add(AuthorizationExpiry)
 }
 
 // FeeDetails represents a single fee associated with a Charge.
@@ -52,26 +101,26 @@ type FeeDetails struct {
 type ChargeParams struct {
 	// A positive integer in cents representing how much to charge the card.
 	// The minimum amount is 50 cents.
-	Amount int64
+	Amount int64 `form:"amount"`
 
 	// 3-letter ISO code for currency. Refer to the Stripe docs for currently
 	// supported currencies: https://support.stripe.com/questions/which-currencies-does-stripe-support
-	Currency string
+	Currency string `form:"currency"`
 
 	// (Optional) Either customer or card is required, but not both The ID of an
 	// existing customer that will be charged in this request.
-	Customer string
+	Customer string `form:"customer,omitempty"`
 
 	// (Optional) Credit Card that should be charged.
-	Card *CardParams
+	Card *CardParams `form:"card,omitempty"`
 
 	// (Optional) Credit Card token that should be charged.
-	Token string
+	Token string `form:"card,omitempty"`
 
 	// An arbitrary string which you can attach to a charge object. It is
 	// displayed when in the web interface alongside the charge. It's often a
 	// good idea to use an email address as a description for tracking later.
-	Desc string
+	Desc string `form:"description"`
 
 	// An arbitrary string to be displayed alongside your company name on your
 	// customer's credit card statement. This may be up to 15 characters. As an
@@ -81,106 +130,293 @@ type ChargeParams struct {
 	// The statement description may not include <>"' characters. While most
 	// banks display this information consistently, some may display it
 	// incorrectly or not at all.
-	StatementDescription string
+	StatementDescription string `form:"statement_description,omitempty"`
+
+	// (Optional) Whether or not to immediately capture the charge. When set to
+	// false, the charge only authorizes the card, and must be captured with
+	// Charges.Capture before the authorization expires. Defaults to true.
+	Capture Optional[bool] `form:"capture"`
+
+	// (Optional) A set of key/value pairs that you can attach to a charge
+	// object, ie an order ID.
+	Metadata map[string]string `form:"metadata,omitempty"`
+}
+
+// appendTo encodes the params. The card parameter holds either a Card or a
+// Token, so a Card takes precedence over a Token, and the Customer is only
+// sent when neither is given.
+func (self *ChargeParams) appendTo(values *url.Values) {
+	if self == nil {
+		return
+	}
+	params := *self
+	if params.Card != nil {
+		params.Token = ""
+	}
+	if params.Card != nil || params.Token != "" {
+		params.Customer = ""
+	}
+	appendParamsToValues(&params, values)
+}
+
+// ChargeUpdateParams encapsulates options for updating a Charge.
+type ChargeUpdateParams struct {
+	// (Optional) An arbitrary string which you can attach to a charge object.
+	Desc Optional[string] `form:"description"`
+
+	// (Optional) A set of key/value pairs that you can attach to a charge
+	// object. Keys that are not given are left unchanged, and a key set to an
+	// empty string is removed.
+	Metadata map[string]string `form:"metadata,omitempty"`
+}
+
+// Validate checks the params against the rules enforced by Stripe, without
+// making a request, and returns a ValidationError listing every invalid
+// parameter.
+func (self *ChargeParams) Validate() error {
+	errs := fieldErrors{}
+	if self.Amount < 50 {
+		errs.add("amount", "must be at least 50 cents")
+	}
+	if !isCurrency(self.Currency) {
+		errs.add("currency", "must be a 3-letter ISO code")
+	}
+	switch {
+	case self.Customer == "" && self.Card == nil && self.Token == "":
+		errs.add("card", "or customer is required")
+	case self.Card != nil && self.Token != "":
+		errs.add("card", "must be either a card or a token, not both")
+	case self.Card != nil:
+		errs.nested("card", self.Card.Validate())
+	}
+	if utf8.RuneCountInString(self.StatementDescription) > 15 {
+		errs.add("statement_description", "must be at most 15 characters")
+	}
+	if strings.ContainsAny(self.StatementDescription, `<>"'`) {
+		errs.add("statement_description", `must not contain <>"' characters`)
+	}
+	return errs.err()
+}
+
+// ChargeListParams encapsulates options for listing Charges.
+type ChargeListParams struct {
+	ListParams
+
+	// (Optional) Only return charges for the customer with this ID.
+	Customer string `form:"customer,omitempty"`
 }
 
 // ChargeClient encapsulates operations for creating, updating, deleting and
 // querying charges using the Stripe REST API.
-type ChargeClient struct{}
+type ChargeClient struct {
+	client *Client
+}
 
 // Creates a new credit card Charge.
 //
 // see https://stripe.com/docs/api#create_charge
-func (self *ChargeClient) Create(params *ChargeParams) (*Charge, error) {
-	charge := Charge{}
-	values := url.Values{
-		"amount":      {strconv.FormatInt(params.Amount, 10)},
-		"currency":    {params.Currency},
-		"description": {params.Desc},
-	}
+func (self *ChargeClient) Create(params *ChargeParams, opts ...RequestOption) (*Charge, error) {
+	return self.CreateContext(context.Background(), params, opts...)
+}
 
-	// add optional credit card details, if specified
-	if params.Card != nil {
-		appendCardParamsToValues(params.Card, &values)
-	} else if len(params.Token) > 0 {
-		values.Add("card", params.Token)
-	} else {
-		// if no credit card is provide we need to specify the customer
-		values.Add("customer", params.Customer)
+// CreateContext is the context-aware version of Create.
+func (self *ChargeClient) CreateContext(ctx context.Context, params *ChargeParams, opts ...RequestOption) (*Charge, error) {
+	charge := Charge{}
+	if err := self.client.validate(params); err != nil {
+		return &charge, err
 	}
+	values := url.Values{}
+	params.appendTo(&values)
 
-	// add optional statment description, if specified
-	if params.StatementDescription != "" {
-		values.Add("statement_description", params.StatementDescription)
+	// the statement description parameter was renamed in newer API versions
+	if self.client.version(opts) >= versionStatementDescriptor {
+		if desc, ok := values["statement_description"]; ok {
+			values["statement_descriptor"] = desc
+			delete(values, "statement_description")
+		}
 	}
 
-	err := query("POST", "/v1/charges", values, &charge)
+	err := self.client.query(ctx, "POST", "/v1/charges", values, &charge, opts...)
 	return &charge, err
 }
 
 // Retrieves the details of a charge with the given ID.
 //
 // see https://stripe.com/docs/api#retrieve_charge
-func (self *ChargeClient) Retrieve(id string) (*Charge, error) {
+func (self *ChargeClient) Retrieve(id string, opts ...RequestOption) (*Charge, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *ChargeClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*Charge, error) {
 	charge := Charge{}
 	path := "/v1/charges/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &charge)
+	err := self.client.query(ctx, "GET", path, nil, &charge, opts...)
 	return &charge, err
 }
 
-// Refunds a charge for the full amount.
+// Updates the description or metadata of the Charge with the given ID.
+//
+// see https://stripe.com/docs/api#update_charge
+func (self *ChargeClient) Update(id string, params *ChargeUpdateParams, opts ...RequestOption) (*Charge, error) {
+	return self.UpdateContext(context.Background(), id, params, opts...)
+}
+
+// UpdateContext is the context-aware version of Update.
+func (self *ChargeClient) UpdateContext(ctx context.Context, id string, params *ChargeUpdateParams, opts ...RequestOption) (*Charge, error) {
+	charge := Charge{}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+
+	err := self.client.query(ctx, "POST", "/v1/charges/"+url.QueryEscape(id), values, &charge, opts...)
+	return &charge, err
+}
+
+// Captures an uncaptured Charge, created with the Capture param set to
+// false. An amount of 0 captures the full amount of the Charge, while a
+// smaller amount captures part of it and refunds the rest.
+//
+// If the authorization has expired, ErrChargeExpiredForCapture is returned.
+//
+// see https://stripe.com/docs/api#charge_capture
+func (self *ChargeClient) Capture(id string, amount int64, opts ...RequestOption) (*Charge, error) {
+	return self.CaptureContext(context.Background(), id, amount, opts...)
+}
+
+// CaptureContext is the context-aware version of Capture.
+func (self *ChargeClient) CaptureContext(ctx context.Context, id string, amount int64, opts ...RequestOption) (*Charge, error) {
+	values := url.Values{}
+	if amount != 0 {
+		values.Add("amount", strconv.FormatInt(amount, 10))
+	}
+	charge := Charge{}
+	path := "/v1/charges/" + url.QueryEscape(id) + "/capture"
+	err := self.client.query(ctx, "POST", path, values, &charge, opts...)
+	return &charge, err
+}
+
+// Refunds a charge for the full amount. Use Refunds.Create to refund with a
+// reason or metadata.
 //
 // see https://stripe.com/docs/api#refund_charge
-func (self *ChargeClient) Refund(id string) (*Charge, error) {
+func (self *ChargeClient) Refund(id string, opts ...RequestOption) (*Charge, error) {
+	return self.RefundContext(context.Background(), id, opts...)
+}
+
+// RefundContext is the context-aware version of Refund.
+func (self *ChargeClient) RefundContext(ctx context.Context, id string, opts ...RequestOption) (*Charge, error) {
 	values := url.Values{}
 	charge := Charge{}
 	path := "/v1/charges/" + url.QueryEscape(id) + "/refund"
-	err := query("POST", path, values, &charge)
+	err := self.client.query(ctx, "POST", path, values, &charge, opts...)
 	return &charge, err
 }
 
 // Refunds a charge for the specified amount.
 //
 // see https://stripe.com/docs/api#refund_charge
-func (self *ChargeClient) RefundAmount(id string, amt int64) (*Charge, error) {
+func (self *ChargeClient) RefundAmount(id string, amt int64, opts ...RequestOption) (*Charge, error) {
+	return self.RefundAmountContext(context.Background(), id, amt, opts...)
+}
+
+// RefundAmountContext is the context-aware version of RefundAmount.
+func (self *ChargeClient) RefundAmountContext(ctx context.Context, id string, amt int64, opts ...RequestOption) (*Charge, error) {
 	values := url.Values{
 		"amount": {strconv.FormatInt(amt, 10)},
 	}
 	charge := Charge{}
 	path := "/v1/charges/" + url.QueryEscape(id) + "/refund"
-	err := query("POST", path, values, &charge)
+	err := self.client.query(ctx, "POST", path, values, &charge, opts...)
 	return &charge, err
 }
 
 // Returns a list of your Charges.
 //
 // see https://stripe.com/docs/api#list_charges
-func (self *ChargeClient) List() ([]*Charge, error) {
-	return self.list("", 10, 0)
+func (self *ChargeClient) List(opts ...RequestOption) ([]*Charge, error) {
+	return self.ListContext(context.Background(), opts...)
+}
+
+// ListContext is the context-aware version of List.
+func (self *ChargeClient) ListContext(ctx context.Context, opts ...RequestOption) ([]*Charge, error) {
+	return self.list(ctx, "", 10, 0, opts...)
 }
 
 // Returns a list of your Charges with the specified range.
 //
 // see https://stripe.com/docs/api#list_charges
-func (self *ChargeClient) ListN(count int, offset int) ([]*Charge, error) {
-	return self.list("", count, offset)
+func (self *ChargeClient) ListN(count int, offset int, opts ...RequestOption) ([]*Charge, error) {
+	return self.ListNContext(context.Background(), count, offset, opts...)
+}
+
+// ListNContext is the context-aware version of ListN.
+func (self *ChargeClient) ListNContext(ctx context.Context, count int, offset int, opts ...RequestOption) ([]*Charge, error) {
+	return self.list(ctx, "", count, offset, opts...)
 }
 
 // Returns a list of your Charges with the given Customer ID.
 //
 // see https://stripe.com/docs/api#list_charges
-func (self *ChargeClient) CustomerList(id string) ([]*Charge, error) {
-	return self.list(id, 10, 0)
+func (self *ChargeClient) CustomerList(id string, opts ...RequestOption) ([]*Charge, error) {
+	return self.CustomerListContext(context.Background(), id, opts...)
+}
+
+// CustomerListContext is the context-aware version of CustomerList.
+func (self *ChargeClient) CustomerListContext(ctx context.Context, id string, opts ...RequestOption) ([]*Charge, error) {
+	return self.list(ctx, id, 10, 0, opts...)
 }
 
 // Returns a list of your Charges with the given Customer ID and range.
 //
 // see https://stripe.com/docs/api#list_charges
-func (self *ChargeClient) CustomerListN(id string, count int, offset int) ([]*Charge, error) {
-	return self.list(id, count, offset)
+func (self *ChargeClient) CustomerListN(id string, count int, offset int, opts ...RequestOption) ([]*Charge, error) {
+	return self.CustomerListNContext(context.Background(), id, count, offset, opts...)
+}
+
+// CustomerListNContext is the context-aware version of CustomerListN.
+func (self *ChargeClient) CustomerListNContext(ctx context.Context, id string, count int, offset int, opts ...RequestOption) ([]*Charge, error) {
+	return self.list(ctx, id, count, offset, opts...)
+}
+
+// Page returns a single page of your Charges, filtered by the given params.
+// The returned List reports whether more Charges are available.
+//
+// see https://stripe.com/docs/api#list_charges
+func (self *ChargeClient) Page(params *ChargeListParams, opts ...RequestOption) (*List[*Charge], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *ChargeClient) PageContext(ctx context.Context, params *ChargeListParams, opts ...RequestOption) (*List[*Charge], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all of your Charges, filtered by the given params,
+// which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_charges
+func (self *ChargeClient) Iter(params *ChargeListParams, opts ...RequestOption) *Iter[*Charge] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *ChargeClient) IterContext(ctx context.Context, params *ChargeListParams, opts ...RequestOption) *Iter[*Charge] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Charge], error) {
+		return self.page(ctx, values, opts...)
+	}, func(c *Charge) string { return c.Id })
+}
+
+func (self *ChargeClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*Charge], error) {
+	list := List[*Charge]{}
+	err := self.client.query(ctx, "GET", "/v1/charges", values, &list, opts...)
+	return &list, err
 }
 
-func (self *ChargeClient) list(id string, count int, offset int) ([]*Charge, error) {
+func (self *ChargeClient) list(ctx context.Context, id string, count int, offset int, opts ...RequestOption) ([]*Charge, error) {
 	// define a wrapper function for the Charge List, so that we can
 	// cleanly parse the JSON
 	type listChargesResp struct{ Data []*Charge }
@@ -197,7 +433,7 @@ func (self *ChargeClient) list(id string, count int, offset int) ([]*Charge, err
 		values.Add("customer", id)
 	}
 
-	err := query("GET", "/v1/charges", values, &resp)
+	err := self.client.query(ctx, "GET", "/v1/charges", values, &resp, opts...)
 	if err != nil {
 		return nil, err
 	}
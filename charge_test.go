@@ -1,6 +1,9 @@
 package stripe
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -147,3 +150,98 @@ func TestRefundCharge(t *testing.T) {
 		return
 	}
 }
+
+// TestCaptureCharge will test that a charge can be authorized, then partially
+// captured, and that capturing an expired authorization returns
+// ErrChargeExpiredForCapture.
+func TestCaptureCharge(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.URL.Path+"?"+r.PostForm.Encode())
+		switch r.URL.Path {
+		case "/v1/charges":
+			w.Write([]byte(`{"id":"ch_1","amount":400,"captured":false,"created":1400000000}`))
+		case "/v1/charges/ch_1/capture":
+			w.Write([]byte(`{"id":"ch_1","amount":400,"amount_refunded":100,"captured":true}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"type":"invalid_request_error","code":"charge_expired_for_capture","message":"Charge ch_2 has expired for capture"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := ChargeParams{Amount: 400, Currency: USD, Customer: "cus_1", Capture: Value(false)}
+	charge, err := client.Charges.Create(&params)
+	if err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+		return
+	}
+	if charge.Captured {
+		t.Errorf("Expected uncaptured Charge")
+	}
+	if want := time.Unix(1400000000, 0).Add(7 * 24 * time.Hour); !charge.CaptureBy().Equal(want) {
+		t.Errorf("Expected CaptureBy %s, got %s", want, charge.CaptureBy())
+	}
+
+	charge, err = client.Charges.Capture("ch_1", 300)
+	if err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+		return
+	}
+	if !charge.Captured || !charge.CaptureBy().IsZero() {
+		t.Errorf("Expected captured Charge")
+	}
+
+	_, err = client.Charges.Capture("ch_2", 0)
+	if !errors.Is(err, ErrChargeExpiredForCapture) {
+		t.Errorf("Expected Error %s, got %v", ErrChargeExpiredForCapture, err)
+	}
+
+	want := []string{
+		"/v1/charges?amount=400&capture=false&currency=usd&customer=cus_1&description=",
+		"/v1/charges/ch_1/capture?amount=300",
+		"/v1/charges/ch_2/capture?",
+	}
+	for i := range want {
+		if i >= len(requests) || requests[i] != want[i] {
+			t.Errorf("Expected request %s, got %v", want[i], requests)
+		}
+	}
+}
+
+// TestUpdateCharge will test that the description and metadata of a charge
+// can be updated, and that metadata is decoded.
+func TestUpdateCharge(t *testing.T) {
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		body = r.PostForm.Encode()
+		w.Write([]byte(`{"id":"ch_1","metadata":{"order_id":"6735"}}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := ChargeUpdateParams{
+		Desc:     Null[string](),
+		Metadata: map[string]string{"order_id": "6735", "cart": ""},
+	}
+	charge, err := client.Charges.Update("ch_1", &params)
+	if err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+		return
+	}
+	if charge.Metadata["order_id"] != "6735" {
+		t.Errorf("Expected Charge Metadata order_id 6735, got %v", charge.Metadata)
+	}
+
+	want := "description=&metadata%5Bcart%5D=&metadata%5Border_id%5D=6735"
+	if body != want {
+		t.Errorf("Expected body %s, got %s", want, body)
+	}
+}
@@ -1,8 +1,10 @@
 package stripe
 
 import (
+	"context"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // Coupon Durations
@@ -28,87 +30,115 @@ type Coupon struct {
 
 // CouponClient encapsulates operations for creating, updating, deleting and
 // querying coupons using the Stripe REST API.
-type CouponClient struct{}
+type CouponClient struct {
+	client *Client
+}
 
 // CouponParams encapsulates options for creating a new Coupon.
 type CouponParams struct {
 	// (Optional) Unique string of your choice that will be used to identify
 	// this coupon when applying it a customer.
-	Id string
+	Id string `form:"id,omitempty"`
 
 	// A positive integer between 1 and 100 that represents the discount the
 	// coupon will apply.
-	PercentOff int
+	PercentOff int `form:"percent_off"`
 
 	// Specifies how long the discount will be in effect. Can be forever, once,
 	// or repeating.
-	Duration string
+	Duration string `form:"duration"`
 
 	// (Optional) If duration is repeating, a positive integer that specifies
 	// the number of months the discount will be in effect.
-	DurationInMonths int
+	DurationInMonths int `form:"duration_in_months,omitempty"`
 
 	// (Optional) A positive integer specifying the number of times the coupon
 	// can be redeemed before it's no longer valid. For example, you might have
 	// a 50% off coupon that the first 20 readers of your blog can use.
-	MaxRedemptions int
+	MaxRedemptions int `form:"max_redemptions,omitempty"`
 
 	// (Optional) UTC timestamp specifying the last time at which the coupon can
 	// be redeemed. After the redeem_by date, the coupon can no longer be
 	// applied to new customers.
-	RedeemBy int64
+	RedeemBy int64 `form:"redeem_by,omitempty"`
 }
 
-// Creates a new Coupon.
-//
-// see https://stripe.com/docs/api#create_coupon
-func (self *CouponClient) Create(params *CouponParams) (*Coupon, error) {
-	coupon := Coupon{}
-	values := url.Values{
-		"duration":    {params.Duration},
-		"percent_off": {strconv.Itoa(params.PercentOff)},
+// Validate checks the params against the rules enforced by Stripe, without
+// making a request, and returns a ValidationError listing every invalid
+// parameter.
+func (self *CouponParams) Validate() error {
+	errs := fieldErrors{}
+	if self.PercentOff < 1 || self.PercentOff > 100 {
+		errs.add("percent_off", "must be between 1 and 100")
 	}
-
-	// coupon id is optional, add if specified
-	if len(params.Id) != 0 {
-		values.Add("id", params.Id)
+	switch self.Duration {
+	case DurationRepeating:
+		if self.DurationInMonths < 1 {
+			errs.add("duration_in_months", "must be positive for a repeating coupon")
+		}
+	case DurationForever, DurationOnce:
+		if self.DurationInMonths != 0 {
+			errs.add("duration_in_months", "only applies to a repeating coupon")
+		}
+	default:
+		errs.add("duration", "must be forever, once or repeating")
 	}
-
-	// duration in months is optional, add if specified
-	if params.DurationInMonths != 0 {
-		values.Add("duration_in_months", strconv.Itoa(params.DurationInMonths))
+	if self.MaxRedemptions < 0 {
+		errs.add("max_redemptions", "must not be negative")
 	}
-
-	// max_redemptions is optional, add if specified
-	if params.MaxRedemptions != 0 {
-		values.Add("max_redemptions", strconv.Itoa(params.MaxRedemptions))
+	if self.RedeemBy != 0 && self.RedeemBy < time.Now().Unix() {
+		errs.add("redeem_by", "must be in the future")
 	}
+	return errs.err()
+}
 
-	// redeem_by is optional, add if specified
-	if params.RedeemBy != 0 {
-		values.Add("redeem_by", strconv.FormatInt(params.RedeemBy, 10))
+// Creates a new Coupon.
+//
+// see https://stripe.com/docs/api#create_coupon
+func (self *CouponClient) Create(params *CouponParams, opts ...RequestOption) (*Coupon, error) {
+	return self.CreateContext(context.Background(), params, opts...)
+}
+
+// CreateContext is the context-aware version of Create.
+func (self *CouponClient) CreateContext(ctx context.Context, params *CouponParams, opts ...RequestOption) (*Coupon, error) {
+	coupon := Coupon{}
+	if err := self.client.validate(params); err != nil {
+		return &coupon, err
 	}
-	err := query("POST", "/v1/coupons", values, &coupon)
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+
+	err := self.client.query(ctx, "POST", "/v1/coupons", values, &coupon, opts...)
 	return &coupon, err
 }
 
 // Retrieves the coupon with the given ID.
 //
 // see https://stripe.com/docs/api#retrieve_coupon
-func (self *CouponClient) Retrieve(id string) (*Coupon, error) {
+func (self *CouponClient) Retrieve(id string, opts ...RequestOption) (*Coupon, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *CouponClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*Coupon, error) {
 	coupon := Coupon{}
 	path := "/v1/coupons/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &coupon)
+	err := self.client.query(ctx, "GET", path, nil, &coupon, opts...)
 	return &coupon, err
 }
 
 // Deletes the coupon with the given ID.
 //
 // see https://stripe.com/docs/api#delete_coupon
-func (self *CouponClient) Delete(id string) (bool, error) {
+func (self *CouponClient) Delete(id string, opts ...RequestOption) (bool, error) {
+	return self.DeleteContext(context.Background(), id, opts...)
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (self *CouponClient) DeleteContext(ctx context.Context, id string, opts ...RequestOption) (bool, error) {
 	resp := DeleteResp{}
 	path := "/v1/coupons/" + url.QueryEscape(id)
-	if err := query("DELETE", path, nil, &resp); err != nil {
+	if err := self.client.query(ctx, "DELETE", path, nil, &resp, opts...); err != nil {
 		return false, err
 	}
 	return resp.Deleted, nil
@@ -117,14 +147,24 @@ func (self *CouponClient) Delete(id string) (bool, error) {
 // Returns a list of your coupons.
 //
 // see https://stripe.com/docs/api#list_coupons
-func (self *CouponClient) List() ([]*Coupon, error) {
-	return self.ListN(10, 0)
+func (self *CouponClient) List(opts ...RequestOption) ([]*Coupon, error) {
+	return self.ListContext(context.Background(), opts...)
+}
+
+// ListContext is the context-aware version of List.
+func (self *CouponClient) ListContext(ctx context.Context, opts ...RequestOption) ([]*Coupon, error) {
+	return self.ListNContext(ctx, 10, 0, opts...)
 }
 
 // Returns a list of your coupons at the specified range.
 //
 // see https://stripe.com/docs/api#list_coupons
-func (self *CouponClient) ListN(count int, offset int) ([]*Coupon, error) {
+func (self *CouponClient) ListN(count int, offset int, opts ...RequestOption) ([]*Coupon, error) {
+	return self.ListNContext(context.Background(), count, offset, opts...)
+}
+
+// ListNContext is the context-aware version of ListN.
+func (self *CouponClient) ListNContext(ctx context.Context, count int, offset int, opts ...RequestOption) ([]*Coupon, error) {
 	// define a wrapper function for the Coupon List, so that we can
 	// cleanly parse the JSON
 	type listCouponResp struct{ Data []*Coupon }
@@ -136,9 +176,47 @@ func (self *CouponClient) ListN(count int, offset int) ([]*Coupon, error) {
 		"offset": {strconv.Itoa(offset)},
 	}
 
-	err := query("GET", "/v1/coupons", values, &resp)
+	err := self.client.query(ctx, "GET", "/v1/coupons", values, &resp, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return resp.Data, nil
 }
+
+// Page returns a single page of your coupons, filtered by the given params.
+// The returned List reports whether more coupons are available.
+//
+// see https://stripe.com/docs/api#list_coupons
+func (self *CouponClient) Page(params *ListParams, opts ...RequestOption) (*List[*Coupon], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *CouponClient) PageContext(ctx context.Context, params *ListParams, opts ...RequestOption) (*List[*Coupon], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all of your coupons, filtered by the given params,
+// which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_coupons
+func (self *CouponClient) Iter(params *ListParams, opts ...RequestOption) *Iter[*Coupon] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *CouponClient) IterContext(ctx context.Context, params *ListParams, opts ...RequestOption) *Iter[*Coupon] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Coupon], error) {
+		return self.page(ctx, values, opts...)
+	}, func(c *Coupon) string { return c.Id })
+}
+
+func (self *CouponClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*Coupon], error) {
+	list := List[*Coupon]{}
+	err := self.client.query(ctx, "GET", "/v1/coupons", values, &list, opts...)
+	return &list, err
+}
@@ -1,6 +1,8 @@
 package stripe
 
 import (
+	"context"
+	"encoding/json"
 	"net/url"
 	"strconv"
 )
@@ -18,17 +20,52 @@ type Customer struct {
 	Cards        CardData      `json:"cards,omitempty"`
 	Discount     *Discount     `json:"discount,omitempty"`
 	Subscription *Subscription `json:"subscription,omitempty"`
-	Livemode     bool          `json:"livemode"`
-	DefaultCard  String        `json:"default_card"`
+
+	// API versions 2014-01-31 and 2015-02-18 replaced the subscription and
+	// cards with the subscriptions and sources lists. Both are always
+	// populated, regardless of the version used.
+	Subscriptions List[*Subscription] `json:"subscriptions"`
+	Sources       CardData            `json:"sources"`
+	DefaultSource Expandable[Card]    `json:"default_source"`
+
+	Livemode    bool             `json:"livemode"`
+	DefaultCard Expandable[Card] `json:"default_card"`
 }
 
-type CardData struct {
-	Object string  `json:"object"`
-	Count  int     `json:"count"`
-	Url    string  `json:"url"`
-	Data   []*Card `json:"data"`
+func (self *Customer) UnmarshalJSON(data []byte) error {
+	type customer Customer
+	if err := json.Unmarshal(data, (*customer)(self)); err != nil {
+		return err
+	}
+
+	if self.Subscription == nil && len(self.Subscriptions.Data) != 0 {
+		self.Subscription = self.Subscriptions.Data[0]
+	} else if self.Subscription != nil && len(self.Subscriptions.Data) == 0 {
+		self.Subscriptions = List[*Subscription]{
+			Object: "list",
+			Count:  1,
+			Data:   []*Subscription{self.Subscription},
+		}
+	}
+
+	if self.Cards.Object == "" {
+		self.Cards = self.Sources
+	} else if self.Sources.Object == "" {
+		self.Sources = self.Cards
+	}
+	if self.DefaultCard.Id == "" {
+		self.DefaultCard = self.DefaultSource
+	} else if self.DefaultSource.Id == "" {
+		self.DefaultSource = self.DefaultCard
+	}
+	return nil
 }
 
+// CardData is the first page of a Customer's cards. The remaining cards can
+// be fetched with Cards.Iter, or with Cards.Page using the starting_after
+// cursor.
+type CardData = List[*Card]
+
 // Discount represents the actual application of a coupon to a particular
 // customer.
 //
@@ -44,88 +81,135 @@ type Discount struct {
 // CustomerParams encapsulates options for creating and updating Customers.
 type CustomerParams struct {
 	// (Optional) The customer's email address.
-	Email string
+	Email Optional[string] `form:"email"`
 
 	// (Optional) An arbitrary string which you can attach to a customer object.
-	Desc string
+	Desc Optional[string] `form:"description"`
 
 	// (Optional) Customer's Active Credit Card
-	Card *CardParams
+	Card *CardParams `form:"card,omitempty"`
 
 	// (Optional) Customer's Active Credid Card, using a Card Token
-	Token string
+	Token string `form:"card,omitempty"`
+
+	// (Optional) The ID of one of the Customer's existing cards, to make the
+	// default card.
+	DefaultCard string `form:"default_card,omitempty"`
 
 	// (Optional) If you provide a coupon code, the customer will have a
 	// discount applied on all recurring charges.
-	Coupon string
+	Coupon string `form:"coupon,omitempty"`
 
 	// (Optional) The identifier of the plan to subscribe the customer to. If
 	// provided, the returned customer object has a 'subscription' attribute
 	// describing the state of the customer's subscription.
-	Plan string
+	Plan string `form:"plan,omitempty"`
 
 	// (Optional) UTC integer timestamp representing the end of the trial period
 	// the customer will get before being charged for the first time.
-	TrialEnd int64
+	TrialEnd int64 `form:"trial_end,omitempty"`
 
 	// (Optional) An integer amount in cents that is the starting account
 	// balance for your customer.
-	AccountBalance int64
+	AccountBalance Optional[int64] `form:"account_balance"`
 
 	// (Optional) A set of key/value pairs that you can attach to a customer
 	// object.
-	Metadata map[string]string
+	Metadata map[string]string `form:"metadata,omitempty"`
 
 	// (Optional) The quantity you’d like to apply to the subscription you’re
 	// creating.
-	Quantity int64
+	Quantity Optional[int64] `form:"quantity"`
+}
+
+// appendTo encodes the params. The card parameter holds either a Card or a
+// Token, so a Card takes precedence over a Token.
+func (self *CustomerParams) appendTo(values *url.Values) {
+	if self == nil {
+		return
+	}
+	params := *self
+	if params.Card != nil {
+		params.Token = ""
+	}
+	appendParamsToValues(&params, values)
 }
 
 // CustomerClient encapsulates operations for creating, updating, deleting and
 // querying customers using the Stripe REST API.
-type CustomerClient struct{}
+type CustomerClient struct {
+	client *Client
+}
 
 // Creates a new Customer.
 //
 // see https://stripe.com/docs/api#create_customer
-func (self *CustomerClient) Create(c *CustomerParams) (*Customer, error) {
+func (self *CustomerClient) Create(c *CustomerParams, opts ...RequestOption) (*Customer, error) {
+	return self.CreateContext(context.Background(), c, opts...)
+}
+
+// CreateContext is the context-aware version of Create.
+func (self *CustomerClient) CreateContext(ctx context.Context, c *CustomerParams, opts ...RequestOption) (*Customer, error) {
 	customer := Customer{}
 	values := url.Values{}
-	appendCustomerParamsToValues(c, &values)
+	c.appendTo(&values)
 
-	err := query("POST", "/v1/customers", values, &customer)
+	err := self.client.query(ctx, "POST", "/v1/customers", values, &customer, opts...)
 	return &customer, err
 }
 
 // Retrieves a Customer with the given ID.
 //
 // see https://stripe.com/docs/api#retrieve_customer
-func (self *CustomerClient) Retrieve(id string) (*Customer, error) {
+func (self *CustomerClient) Retrieve(id string, opts ...RequestOption) (*Customer, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *CustomerClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*Customer, error) {
 	customer := Customer{}
 	path := "/v1/customers/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &customer)
+	err := self.client.query(ctx, "GET", path, nil, &customer, opts...)
 	return &customer, err
 }
 
 // Updates a Customer with the given ID.
 //
 // see https://stripe.com/docs/api#update_customer
-func (self *CustomerClient) Update(id string, c *CustomerParams) (*Customer, error) {
+func (self *CustomerClient) Update(id string, c *CustomerParams, opts ...RequestOption) (*Customer, error) {
+	return self.UpdateContext(context.Background(), id, c, opts...)
+}
+
+// UpdateContext is the context-aware version of Update.
+func (self *CustomerClient) UpdateContext(ctx context.Context, id string, c *CustomerParams, opts ...RequestOption) (*Customer, error) {
 	customer := Customer{}
 	values := url.Values{}
-	appendCustomerParamsToValues(c, &values)
+	c.appendTo(&values)
 
-	err := query("POST", "/v1/customers/"+url.QueryEscape(id), values, &customer)
+	// the default card parameter was renamed in newer API versions
+	if self.client.version(opts) >= versionSources {
+		if card, ok := values["default_card"]; ok {
+			values["default_source"] = card
+			delete(values, "default_card")
+		}
+	}
+
+	err := self.client.query(ctx, "POST", "/v1/customers/"+url.QueryEscape(id), values, &customer, opts...)
 	return &customer, err
 }
 
 // Deletes a Customer (permanently) with the given ID.
 //
 // see https://stripe.com/docs/api#delete_customer
-func (self *CustomerClient) Delete(id string) (bool, error) {
+func (self *CustomerClient) Delete(id string, opts ...RequestOption) (bool, error) {
+	return self.DeleteContext(context.Background(), id, opts...)
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (self *CustomerClient) DeleteContext(ctx context.Context, id string, opts ...RequestOption) (bool, error) {
 	resp := DeleteResp{}
 	path := "/v1/customers/" + url.QueryEscape(id)
-	if err := query("DELETE", path, nil, &resp); err != nil {
+	if err := self.client.query(ctx, "DELETE", path, nil, &resp, opts...); err != nil {
 		return false, err
 	}
 	return resp.Deleted, nil
@@ -134,14 +218,24 @@ func (self *CustomerClient) Delete(id string) (bool, error) {
 // Returns a list of your Customers.
 //
 // see https://stripe.com/docs/api#list_customers
-func (self *CustomerClient) List() ([]*Customer, error) {
-	return self.ListN(10, 0)
+func (self *CustomerClient) List(opts ...RequestOption) ([]*Customer, error) {
+	return self.ListContext(context.Background(), opts...)
+}
+
+// ListContext is the context-aware version of List.
+func (self *CustomerClient) ListContext(ctx context.Context, opts ...RequestOption) ([]*Customer, error) {
+	return self.ListNContext(ctx, 10, 0, opts...)
 }
 
 // Returns a list of your Customers at the specified range.
 //
 // see https://stripe.com/docs/api#list_customers
-func (self *CustomerClient) ListN(count int, offset int) ([]*Customer, error) {
+func (self *CustomerClient) ListN(count int, offset int, opts ...RequestOption) ([]*Customer, error) {
+	return self.ListNContext(context.Background(), count, offset, opts...)
+}
+
+// ListNContext is the context-aware version of ListN.
+func (self *CustomerClient) ListNContext(ctx context.Context, count int, offset int, opts ...RequestOption) ([]*Customer, error) {
 	// define a wrapper function for the Customer List, so that we can
 	// cleanly parse the JSON
 	type listCustomerResp struct{ Data []*Customer }
@@ -153,76 +247,47 @@ func (self *CustomerClient) ListN(count int, offset int) ([]*Customer, error) {
 		"offset": {strconv.Itoa(offset)},
 	}
 
-	err := query("GET", "/v1/customers", values, &resp)
+	err := self.client.query(ctx, "GET", "/v1/customers", values, &resp, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return resp.Data, nil
 }
 
-////////////////////////////////////////////////////////////////////////////////
-// Helper Function(s)
+// Page returns a single page of your Customers, filtered by the given params.
+// The returned List reports whether more Customers are available.
+//
+// see https://stripe.com/docs/api#list_customers
+func (self *CustomerClient) Page(params *ListParams, opts ...RequestOption) (*List[*Customer], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
 
-func appendCustomerParamsToValues(c *CustomerParams, values *url.Values) {
-	// add optional parameters, if specified
-	if c.Email != "" {
-		values.Add("email", c.Email)
-	}
-	if c.Desc != "" {
-		values.Add("description", c.Desc)
-	}
-	if c.Coupon != "" {
-		values.Add("coupon", c.Coupon)
-	}
-	if c.Plan != "" {
-		values.Add("plan", c.Plan)
-	}
-	if c.TrialEnd != 0 {
-		values.Add("trial_end", strconv.FormatInt(c.TrialEnd, 10))
-	}
-	if c.AccountBalance != 0 {
-		values.Add("account_balance", strconv.FormatInt(c.AccountBalance, 10))
-	}
-	if c.Quantity != 0 {
-		values.Add("quantity", strconv.FormatInt(c.Quantity, 10))
-	}
+// PageContext is the context-aware version of Page.
+func (self *CustomerClient) PageContext(ctx context.Context, params *ListParams, opts ...RequestOption) (*List[*Customer], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
 
-	// add metadata, if specified
-	for k, v := range c.Metadata {
-		values.Add("metadata["+k+"]", v)
-	}
+// Iter returns an iterator over all of your Customers, filtered by the given params,
+// which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_customers
+func (self *CustomerClient) Iter(params *ListParams, opts ...RequestOption) *Iter[*Customer] {
+	return self.IterContext(context.Background(), params, opts...)
+}
 
-	// add optional credit card details, if specified
-	if c.Card != nil {
-		appendCardParamsToValues(c.Card, values)
-	} else if len(c.Token) != 0 {
-		values.Add("card", c.Token)
-	}
+// IterContext is the context-aware version of Iter.
+func (self *CustomerClient) IterContext(ctx context.Context, params *ListParams, opts ...RequestOption) *Iter[*Customer] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Customer], error) {
+		return self.page(ctx, values, opts...)
+	}, func(c *Customer) string { return c.Id })
 }
 
-func appendCardParamsToValues(c *CardParams, values *url.Values) {
-	values.Add("card[number]", c.Number)
-	values.Add("card[exp_month]", strconv.Itoa(c.ExpMonth))
-	values.Add("card[exp_year]", strconv.Itoa(c.ExpYear))
-	if c.Name != "" {
-		values.Add("card[name]", c.Name)
-	}
-	if c.CVC != "" {
-		values.Add("card[cvc]", c.CVC)
-	}
-	if c.Address1 != "" {
-		values.Add("card[address_line1]", c.Address1)
-	}
-	if c.Address2 != "" {
-		values.Add("card[address_line2]", c.Address2)
-	}
-	if c.AddressZip != "" {
-		values.Add("card[address_zip]", c.AddressZip)
-	}
-	if c.AddressState != "" {
-		values.Add("card[address_state]", c.AddressState)
-	}
-	if c.AddressCountry != "" {
-		values.Add("card[address_country]", c.AddressCountry)
-	}
+func (self *CustomerClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*Customer], error) {
+	list := List[*Customer]{}
+	err := self.client.query(ctx, "GET", "/v1/customers", values, &list, opts...)
+	return &list, err
 }
@@ -17,14 +17,14 @@ func init() {
 var (
 	// Customer with only the required fields
 	cust1 = CustomerParams{
-		Email: "test1@test.com",
-		Desc:  "a test customer",
+		Email: Value("test1@test.com"),
+		Desc:  Value("a test customer"),
 	}
 
 	// Customer with all required fields + required credit card fields.
 	cust2 = CustomerParams{
-		Email:  "test2@test.com",
-		Desc:   "a 2nd test customer",
+		Email:  Value("test2@test.com"),
+		Desc:   Value("a 2nd test customer"),
 		Coupon: c1.Id,
 		Plan:   p1.Id,
 		Card: &CardParams{
@@ -37,14 +37,14 @@ var (
 
 	// Another Customer with only the required fields
 	cust3 = CustomerParams{
-		Email: "test3@test.com",
-		Desc:  "a 3rd test customer",
+		Email: Value("test3@test.com"),
+		Desc:  Value("a 3rd test customer"),
 	}
 
 	// A customer with the required fields + a credit card
 	cust4 = CustomerParams{
-		Email: "test3@test.com",
-		Desc:  "a 3rd test customer",
+		Email: Value("test3@test.com"),
+		Desc:  Value("a 3rd test customer"),
 		Card: &CardParams{
 			Name:     "John Smith",
 			Number:   "4242424242424242",
@@ -65,11 +65,11 @@ func TestCreateCustomer(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected Customer, got Error %s", err.Error())
 	}
-	if string(cust.Email) != cust1.Email {
-		t.Errorf("Expected Customer Email %s, got %v", cust1.Email, cust.Email)
+	if want, _ := cust1.Email.Get(); string(cust.Email) != want {
+		t.Errorf("Expected Customer Email %s, got %v", want, cust.Email)
 	}
-	if string(cust.Desc) != cust1.Desc {
-		t.Errorf("Expected Customer Desc %s, got %v", cust1.Desc, cust.Desc)
+	if want, _ := cust1.Desc.Get(); string(cust.Desc) != want {
+		t.Errorf("Expected Customer Desc %s, got %v", want, cust.Desc)
 	}
 }
 
@@ -83,7 +83,7 @@ func TestCreateCustomerToken(t *testing.T) {
 	// Create a Charge that uses a Token
 	cust := CustomerParams{
 		Token: token.Id,
-		Desc:  "Customer for site@stripe.com",
+		Desc:  Value("Customer for site@stripe.com"),
 	}
 
 	// Create the charge
@@ -124,11 +124,11 @@ func TestRetrieveCustomer(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected Customer, got Error %s", err.Error())
 	}
-	if string(cust.Email) != cust2.Email {
-		t.Errorf("Expected Customer Email %s, got %v", cust2.Email, cust.Email)
+	if want, _ := cust2.Email.Get(); string(cust.Email) != want {
+		t.Errorf("Expected Customer Email %s, got %v", want, cust.Email)
 	}
-	if string(cust.Desc) != cust2.Desc {
-		t.Errorf("Expected Customer Desc %s, got %v", cust2.Desc, cust.Desc)
+	if want, _ := cust2.Desc.Get(); string(cust.Desc) != want {
+		t.Errorf("Expected Customer Desc %s, got %v", want, cust.Desc)
 	}
 	if cust.Cards.Count == 0 {
 		t.Errorf("Expected Credit Card %s, got nil", cust2.Card.Number)
@@ -159,7 +159,7 @@ func TestUpdateCustomer(t *testing.T) {
 	resp, _ := Customers.Create(&cust1)
 	defer Customers.Delete(resp.Id)
 
-	cust, err := Customers.Update(resp.Id, &CustomerParams{Email: "joe@email.com"})
+	cust, err := Customers.Update(resp.Id, &CustomerParams{Email: Value("joe@email.com")})
 	if err != nil {
 		t.Errorf("Expected Customer update, got Error %s", err.Error())
 	}
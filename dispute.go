@@ -0,0 +1,237 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// Dispute Reasons
+const (
+	DisputeReasonDuplicate            = "duplicate"
+	DisputeReasonFraudulent           = "fraudulent"
+	DisputeReasonSubscriptionCanceled = "subscription_canceled"
+	DisputeReasonProductUnacceptable  = "product_unacceptable"
+	DisputeReasonProductNotReceived   = "product_not_received"
+	DisputeReasonUnrecognized         = "unrecognized"
+	DisputeReasonCreditNotProcessed   = "credit_not_processed"
+	DisputeReasonGeneral              = "general"
+)
+
+// Dispute Statuses
+const (
+	DisputeStatusWarningNeedsResponse = "warning_needs_response"
+	DisputeStatusWarningUnderReview   = "warning_under_review"
+	DisputeStatusWarningClosed        = "warning_closed"
+	DisputeStatusNeedsResponse        = "needs_response"
+	DisputeStatusResponseDisabled     = "response_disabled"
+	DisputeStatusUnderReview          = "under_review"
+	DisputeStatusChargeRefunded       = "charge_refunded"
+	DisputeStatusWon                  = "won"
+	DisputeStatusLost                 = "lost"
+)
+
+// Dispute represents a chargeback, where a customer has disputed a Charge
+// with their bank. The disputed amount is withdrawn from your balance until
+// the dispute is resolved.
+//
+// see https://stripe.com/docs/api#dispute_object
+type Dispute struct {
+	Id                  string                 `json:"id"`
+	Charge              Expandable[Charge]     `json:"charge"`
+	Amount              int64                  `json:"amount"`
+	Currency            string                 `json:"currency"`
+	Created             int64                  `json:"created"`
+	Reason              string                 `json:"reason"`
+	Status              string                 `json:"status"`
+	Evidence            DisputeEvidence        `json:"evidence"`
+	EvidenceDueBy       int64                  `json:"evidence_due_by"`
+	EvidenceDetails     DisputeEvidenceDetails `json:"evidence_details"`
+	BalanceTransactions []*BalanceTransaction  `json:"balance_transactions"`
+	IsChargeRefundable  bool                   `json:"is_charge_refundable"`
+	Livemode            bool                   `json:"livemode"`
+	Metadata            map[string]string      `json:"metadata"`
+}
+
+func (self *Dispute) UnmarshalJSON(data []byte) error {
+	type dispute Dispute
+	if err := json.Unmarshal(data, (*dispute)(self)); err != nil {
+		return err
+	}
+
+	// newer API versions moved the evidence due date to the evidence
+	// details, so we populate both fields regardless of the version used.
+	if self.EvidenceDueBy == 0 {
+		self.EvidenceDueBy = self.EvidenceDetails.DueBy
+	} else if self.EvidenceDetails.DueBy == 0 {
+		self.EvidenceDetails.DueBy = self.EvidenceDueBy
+	}
+	return nil
+}
+
+// DisputeEvidence is the evidence submitted to the bank to challenge a
+// Dispute. Documents (ie Receipt) are the IDs of Files uploaded with the
+// FilePurposeDisputeEvidence purpose.
+type DisputeEvidence struct {
+	ProductDescription     string `json:"product_description" form:"product_description,omitempty"`
+	CustomerName           string `json:"customer_name" form:"customer_name,omitempty"`
+	CustomerEmailAddress   string `json:"customer_email_address" form:"customer_email_address,omitempty"`
+	BillingAddress         string `json:"billing_address" form:"billing_address,omitempty"`
+	Receipt                string `json:"receipt" form:"receipt,omitempty"`
+	CustomerCommunication  string `json:"customer_communication" form:"customer_communication,omitempty"`
+	ServiceDate            string `json:"service_date" form:"service_date,omitempty"`
+	ShippingAddress        string `json:"shipping_address" form:"shipping_address,omitempty"`
+	ShippingCarrier        string `json:"shipping_carrier" form:"shipping_carrier,omitempty"`
+	ShippingDate           string `json:"shipping_date" form:"shipping_date,omitempty"`
+	ShippingTrackingNumber string `json:"shipping_tracking_number" form:"shipping_tracking_number,omitempty"`
+	ShippingDocumentation  string `json:"shipping_documentation" form:"shipping_documentation,omitempty"`
+	RefundPolicy           string `json:"refund_policy" form:"refund_policy,omitempty"`
+	UncategorizedText      string `json:"uncategorized_text" form:"uncategorized_text,omitempty"`
+	UncategorizedFile      string `json:"uncategorized_file" form:"uncategorized_file,omitempty"`
+}
+
+// UnmarshalJSON decodes the evidence object, or the free form text that
+// older API versions use for evidence, as the UncategorizedText.
+func (self *DisputeEvidence) UnmarshalJSON(data []byte) error {
+	text := ""
+	if err := json.Unmarshal(data, &text); err == nil {
+		*self = DisputeEvidence{UncategorizedText: text}
+		return nil
+	}
+
+	type evidence DisputeEvidence
+	return json.Unmarshal(data, (*evidence)(self))
+}
+
+// DisputeEvidenceDetails describes the state of the evidence of a Dispute.
+type DisputeEvidenceDetails struct {
+	DueBy           int64 `json:"due_by"`
+	HasEvidence     bool  `json:"has_evidence"`
+	PastDue         bool  `json:"past_due"`
+	SubmissionCount int   `json:"submission_count"`
+}
+
+// DisputeUpdateParams encapsulates options for updating a Dispute.
+type DisputeUpdateParams struct {
+	// (Optional) Evidence to challenge the Dispute. Fields that are not given
+	// are left unchanged.
+	Evidence *DisputeEvidence `form:"evidence,omitempty"`
+
+	// (Optional) Whether to immediately submit the evidence to the bank.
+	// When set to false, the evidence is only staged, and can be updated
+	// again before it is submitted. Defaults to true.
+	Submit Optional[bool] `form:"submit"`
+
+	// (Optional) A set of key/value pairs that you can attach to a dispute
+	// object.
+	Metadata map[string]string `form:"metadata,omitempty"`
+}
+
+// DisputeClient encapsulates operations for updating, closing and querying
+// disputes using the Stripe REST API.
+type DisputeClient struct {
+	client *Client
+}
+
+// Retrieves the Dispute with the given ID.
+//
+// see https://stripe.com/docs/api#retrieve_dispute
+func (self *DisputeClient) Retrieve(id string, opts ...RequestOption) (*Dispute, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *DisputeClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*Dispute, error) {
+	dispute := Dispute{}
+	path := "/v1/disputes/" + url.QueryEscape(id)
+	err := self.client.query(ctx, "GET", path, nil, &dispute, opts...)
+	return &dispute, err
+}
+
+// Updates the evidence or metadata of the Dispute with the given ID.
+//
+// API versions before 2014-12-08 only accept free form text as evidence, in
+// which case the Evidence may hold nothing but the UncategorizedText.
+//
+// see https://stripe.com/docs/api#update_dispute
+func (self *DisputeClient) Update(id string, params *DisputeUpdateParams, opts ...RequestOption) (*Dispute, error) {
+	return self.UpdateContext(context.Background(), id, params, opts...)
+}
+
+// UpdateContext is the context-aware version of Update.
+func (self *DisputeClient) UpdateContext(ctx context.Context, id string, params *DisputeUpdateParams, opts ...RequestOption) (*Dispute, error) {
+	dispute := Dispute{}
+	values := url.Values{}
+	if params == nil || params.Evidence == nil || self.client.version(opts) >= versionDisputeEvidence {
+		appendParamsToValues(params, &values)
+	} else {
+		// older API versions only accept free form text as evidence
+		text := DisputeEvidence{UncategorizedText: params.Evidence.UncategorizedText}
+		if *params.Evidence != text {
+			errs := fieldErrors{}
+			errs.add("evidence", "must only hold uncategorized text before API version "+versionDisputeEvidence)
+			return &dispute, errs.err()
+		}
+		textParams := *params
+		textParams.Evidence = nil
+		appendParamsToValues(&textParams, &values)
+		values.Set("evidence", text.UncategorizedText)
+	}
+
+	err := self.client.query(ctx, "POST", "/v1/disputes/"+url.QueryEscape(id), values, &dispute, opts...)
+	return &dispute, err
+}
+
+// Closes the Dispute with the given ID, accepting it as lost. Closing a
+// dispute is irreversible.
+//
+// see https://stripe.com/docs/api#close_dispute
+func (self *DisputeClient) Close(id string, opts ...RequestOption) (*Dispute, error) {
+	return self.CloseContext(context.Background(), id, opts...)
+}
+
+// CloseContext is the context-aware version of Close.
+func (self *DisputeClient) CloseContext(ctx context.Context, id string, opts ...RequestOption) (*Dispute, error) {
+	dispute := Dispute{}
+	path := "/v1/disputes/" + url.QueryEscape(id) + "/close"
+	err := self.client.query(ctx, "POST", path, url.Values{}, &dispute, opts...)
+	return &dispute, err
+}
+
+// Page returns a single page of your Disputes, filtered by the given params.
+// The returned List reports whether more Disputes are available.
+//
+// see https://stripe.com/docs/api#list_disputes
+func (self *DisputeClient) Page(params *ListParams, opts ...RequestOption) (*List[*Dispute], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *DisputeClient) PageContext(ctx context.Context, params *ListParams, opts ...RequestOption) (*List[*Dispute], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all of your Disputes, filtered by the given
+// params, which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_disputes
+func (self *DisputeClient) Iter(params *ListParams, opts ...RequestOption) *Iter[*Dispute] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *DisputeClient) IterContext(ctx context.Context, params *ListParams, opts ...RequestOption) *Iter[*Dispute] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Dispute], error) {
+		return self.page(ctx, values, opts...)
+	}, func(d *Dispute) string { return d.Id })
+}
+
+func (self *DisputeClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*Dispute], error) {
+	list := List[*Dispute]{}
+	err := self.client.query(ctx, "GET", "/v1/disputes", values, &list, opts...)
+	return &list, err
+}
@@ -0,0 +1,129 @@
+package stripe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestChargeDispute will test that the dispute of a Charge is decoded, both
+// as the object and text evidence of older API versions, and as the ID of
+// newer API versions.
+func TestChargeDispute(t *testing.T) {
+	old := `{"id":"ch_1","disputed":true,"dispute":{"charge":"ch_1","amount":400,"status":"needs_response",` +
+		`"reason":"fraudulent","evidence":"Signed for by G. Costanza","evidence_due_by":1400000000,` +
+		`"balance_transactions":[{"id":"txn_1","amount":-400,"type":"adjustment"}]}}`
+	charge := Charge{}
+	if err := json.Unmarshal([]byte(old), &charge); err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+		return
+	}
+	dispute := charge.Dispute.Object
+	if dispute == nil || dispute.Amount != 400 || dispute.Status != DisputeStatusNeedsResponse {
+		t.Errorf("Expected Dispute of 400 needing a response, got %+v", dispute)
+		return
+	}
+	if dispute.Evidence.UncategorizedText != "Signed for by G. Costanza" {
+		t.Errorf("Expected text evidence, got %+v", dispute.Evidence)
+	}
+	if dispute.EvidenceDetails.DueBy != 1400000000 {
+		t.Errorf("Expected evidence due by 1400000000, got %d", dispute.EvidenceDetails.DueBy)
+	}
+	if len(dispute.BalanceTransactions) != 1 || dispute.BalanceTransactions[0].Amount != -400 {
+		t.Errorf("Expected 1 balance transaction of -400, got %v", dispute.BalanceTransactions)
+	}
+
+	charge = Charge{}
+	json.Unmarshal([]byte(`{"id":"ch_1","dispute":"dp_1"}`), &charge)
+	if charge.Dispute.Id != "dp_1" || charge.Dispute.Expanded() {
+		t.Errorf("Expected Dispute ID dp_1, got %+v", charge.Dispute)
+	}
+}
+
+// TestUpdateDispute will test that evidence is submitted, and that a dispute
+// can be closed.
+func TestUpdateDispute(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.URL.Path+"?"+r.PostForm.Encode())
+		w.Write([]byte(`{"id":"dp_1","status":"under_review","evidence":{"receipt":"file_1"},` +
+			`"evidence_details":{"due_by":1400000000,"submission_count":1}}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := DisputeUpdateParams{
+		Evidence: &DisputeEvidence{
+			Receipt:                "file_1",
+			ShippingTrackingNumber: "1Z999",
+		},
+		Submit: Value(false),
+	}
+	dispute, err := client.Disputes.Update("dp_1", &params, APIVersion("2014-12-08"))
+	if err != nil {
+		t.Errorf("Expected Dispute, got Error %s", err.Error())
+		return
+	}
+	if dispute.Evidence.Receipt != "file_1" || dispute.EvidenceDueBy != 1400000000 {
+		t.Errorf("Expected Dispute evidence and due date, got %+v", dispute)
+	}
+	client.Disputes.Close("dp_1")
+
+	want := []string{
+		"/v1/disputes/dp_1?evidence%5Breceipt%5D=file_1&evidence%5Bshipping_tracking_number%5D=1Z999&submit=false",
+		"/v1/disputes/dp_1/close?",
+	}
+	for i := range want {
+		if i >= len(requests) || requests[i] != want[i] {
+			t.Errorf("Expected request %s, got %v", want[i], requests)
+		}
+	}
+}
+
+// TestUpdateDisputeVersion will test that evidence is sent as free form text
+// with the default API version, and as a hash of fields with newer versions.
+func TestUpdateDisputeVersion(t *testing.T) {
+	versions, bodies := []string{}, []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		versions = append(versions, r.Header.Get("Stripe-Version"))
+		bodies = append(bodies, r.PostForm.Encode())
+		w.Write([]byte(`{"id":"dp_1"}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := DisputeUpdateParams{
+		Evidence: &DisputeEvidence{UncategorizedText: "Delivered"},
+		Metadata: map[string]string{"ticket": "42"},
+	}
+	client.Disputes.Update("dp_1", &params)
+	client.Disputes.Update("dp_1", &params, APIVersion("2014-12-08"))
+
+	want := []string{
+		"evidence=Delivered&metadata%5Bticket%5D=42",
+		"evidence%5Buncategorized_text%5D=Delivered&metadata%5Bticket%5D=42",
+	}
+	if len(bodies) != 2 || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("Expected bodies %v, got %v", want, bodies)
+	}
+	if len(versions) != 2 || versions[0] != "2013-08-13" || versions[1] != "2014-12-08" {
+		t.Errorf("Expected versions 2013-08-13 and 2014-12-08, got %v", versions)
+	}
+
+	// evidence fields cannot be sent with the default API version
+	params.Evidence = &DisputeEvidence{Receipt: "file_1"}
+	_, err := client.Disputes.Update("dp_1", &params)
+	if invalid, ok := err.(*ValidationError); !ok || invalid.Fields[0].Param != "evidence" {
+		t.Errorf("Expected ValidationError for evidence, got %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Errorf("Expected no request for invalid evidence, got %v", bodies[2:])
+	}
+}
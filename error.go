@@ -1,5 +1,12 @@
 package stripe
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
 // Stripe-provided error codes and types
 // See https://stripe.com/docs/api#errors
 const (
@@ -18,20 +25,172 @@ const (
 	ErrCodeCardDeclined       = "card_declined"
 	ErrCodeMissing            = "missing"
 	ErrCodeProcessingError    = "processing_error"
+
+	ErrCodeChargeAlreadyCaptured   = "charge_already_captured"
+	ErrCodeChargeExpiredForCapture = "charge_expired_for_capture"
+)
+
+// Sentinel errors that can be used with errors.Is to test the type, code or
+// HTTP status of an error returned by the Stripe REST API. For example:
+//
+//	if errors.Is(err, stripe.ErrCardDeclined) {
+//		...
+//	}
+var (
+	ErrInvalidRequest = newSentinel(0, ErrTypeInvalidRequest, "")
+	ErrAPI            = newSentinel(0, ErrTypeAPI, "")
+	ErrCard           = newSentinel(0, ErrTypeCard, "")
+
+	ErrIncorrectNumber    = newSentinel(0, "", ErrCodeIncorrectNumber)
+	ErrInvalidNumber      = newSentinel(0, "", ErrCodeInvalidNumber)
+	ErrInvalidExpiryMonth = newSentinel(0, "", ErrCodeInvalidExpiryMonth)
+	ErrInvalidExpiryYear  = newSentinel(0, "", ErrCodeInvalidExpiryYear)
+	ErrInvalidCVC         = newSentinel(0, "", ErrCodeInvalidCVC)
+	ErrExpiredCard        = newSentinel(0, "", ErrCodeExpiredCard)
+	ErrIncorrectCVC       = newSentinel(0, "", ErrCodeIncorrectCVC)
+	ErrIncorrectZIP       = newSentinel(0, "", ErrCodeIncorrectZIP)
+	ErrCardDeclined       = newSentinel(0, "", ErrCodeCardDeclined)
+	ErrMissing            = newSentinel(0, "", ErrCodeMissing)
+	ErrProcessingError    = newSentinel(0, "", ErrCodeProcessingError)
+
+	ErrChargeAlreadyCaptured   = newSentinel(0, "", ErrCodeChargeAlreadyCaptured)
+	ErrChargeExpiredForCapture = newSentinel(0, "", ErrCodeChargeExpiredForCapture)
+
+	ErrAuthentication = newSentinel(http.StatusUnauthorized, "", "")
+	ErrRateLimit      = newSentinel(http.StatusTooManyRequests, "", "")
 )
 
 // Error encapsulates an error returned by the Stripe REST API.
-// Detail.Code and Detail.Param may be empty.
+// Detail.Code, Detail.Param, Detail.DeclineCode and Detail.Charge may be
+// empty.
 type Error struct {
-	Code   int
+	// The HTTP status code of the response.
+	Code int `json:"-"`
+
+	// The ID Stripe assigned to the request (the Request-Id header), which
+	// Stripe support will ask for when investigating a failure.
+	RequestId string `json:"-"`
+
+	// The number of attempts made before giving up on the request.
+	Attempts int `json:"-"`
+
 	Detail struct {
-		Type    string `json:"type"`
-		Message string `json:"message"`
-		Code    string `json:"code,omitempty"`
-		Param   string `json:"param,omitempty"`
+		Type        string `json:"type"`
+		Message     string `json:"message"`
+		Code        string `json:"code,omitempty"`
+		Param       string `json:"param,omitempty"`
+		DeclineCode string `json:"decline_code,omitempty"`
+		Charge      string `json:"charge,omitempty"`
 	} `json:"error"`
 }
 
 func (e *Error) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s (after %d attempts)", e.Detail.Message, e.Attempts)
+	}
 	return e.Detail.Message
 }
+
+// Is reports whether the error matches the target, which is typically one
+// of the sentinel errors (ie ErrCardDeclined). Empty fields of the target
+// match any value.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return (t.Code == 0 || t.Code == e.Code) &&
+		(t.Detail.Type == "" || t.Detail.Type == e.Detail.Type) &&
+		(t.Detail.Code == "" || t.Detail.Code == e.Detail.Code)
+}
+
+// AuthenticationError is returned when Stripe rejects the API key used to
+// authenticate the request (HTTP 401).
+type AuthenticationError struct {
+	Err *Error
+}
+
+func (e *AuthenticationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AuthenticationError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when too many requests hit the Stripe REST API
+// too quickly (HTTP 429), and retrying did not help.
+type RateLimitError struct {
+	Err *Error
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// ConnectionError is returned when the Stripe REST API could not be reached,
+// or the connection failed before a response was received.
+type ConnectionError struct {
+	// The underlying network error of the last attempt.
+	Err error
+
+	// The number of attempts made before giving up on the request.
+	Attempts int
+}
+
+func (e *ConnectionError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("stripe: request failed after %d attempts: %s", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("stripe: request failed: %s", e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// newError decodes the error returned in a non-200 http.Response. A body that
+// is not a valid Stripe error still produces a meaningful api_error.
+func newError(r *http.Response, body []byte, attempts int) error {
+	e := &Error{
+		Code:      r.StatusCode,
+		RequestId: r.Header.Get("Request-Id"),
+		Attempts:  attempts,
+	}
+	if err := json.Unmarshal(body, e); err != nil || e.Detail.Message == "" {
+		if e.Detail.Type == "" {
+			e.Detail.Type = ErrTypeAPI
+		}
+		e.Detail.Message = fmt.Sprintf("stripe: unexpected response %q: %s",
+			r.Status, strings.TrimSpace(string(body)))
+	}
+
+	switch r.StatusCode {
+	case http.StatusUnauthorized:
+		return &AuthenticationError{e}
+	case http.StatusTooManyRequests:
+		return &RateLimitError{e}
+	}
+	return e
+}
+
+// newSentinel returns an Error matching the given HTTP status, type and code,
+// for use with errors.Is.
+func newSentinel(status int, typ, code string) *Error {
+	e := &Error{Code: status}
+	e.Detail.Type = typ
+	e.Detail.Code = code
+	switch {
+	case code != "":
+		e.Detail.Message = "stripe: " + code
+	case typ != "":
+		e.Detail.Message = "stripe: " + typ
+	default:
+		e.Detail.Message = "stripe: " + strings.ToLower(http.StatusText(status))
+	}
+	return e
+}
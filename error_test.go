@@ -1,6 +1,10 @@
 package stripe
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -82,3 +86,78 @@ func TestBadCards(t *testing.T) {
 		}
 	}
 }
+
+// TestErrorDecoding will test that a Stripe error response is decoded with
+// its HTTP status, request ID, decline code and charge ID, and can be matched
+// with errors.Is.
+func TestErrorDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Request-Id", "req_123")
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"error":{"type":"card_error","message":"Your card was declined.",` +
+			`"code":"card_declined","decline_code":"insufficient_funds","charge":"ch_1"}}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+	_, err := client.Charges.Retrieve("ch_1")
+
+	if !errors.Is(err, ErrCardDeclined) || !errors.Is(err, ErrCard) {
+		t.Errorf("Expected Error to match ErrCardDeclined and ErrCard, got %v", err)
+	}
+	if errors.Is(err, ErrExpiredCard) || errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Expected Error not to match ErrExpiredCard or ErrInvalidRequest")
+	}
+
+	stripeErr := err.(*Error)
+	if stripeErr.Code != http.StatusPaymentRequired {
+		t.Errorf("Expected Error Code %d, got %d", http.StatusPaymentRequired, stripeErr.Code)
+	}
+	if stripeErr.RequestId != "req_123" {
+		t.Errorf("Expected Error RequestId req_123, got %s", stripeErr.RequestId)
+	}
+	if stripeErr.Detail.DeclineCode != "insufficient_funds" {
+		t.Errorf("Expected Error DeclineCode insufficient_funds, got %s", stripeErr.Detail.DeclineCode)
+	}
+	if stripeErr.Detail.Charge != "ch_1" {
+		t.Errorf("Expected Error Charge ch_1, got %s", stripeErr.Detail.Charge)
+	}
+}
+
+// TestErrorTypes will test that authentication failures and malformed error
+// responses are decoded into the expected error types.
+func TestErrorTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/charges/ch_401" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"Invalid API Key provided"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`<html>Bad Gateway</html>`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+	client.MaxRetries = 0
+
+	_, err := client.Charges.Retrieve("ch_401")
+	if _, ok := err.(*AuthenticationError); !ok {
+		t.Errorf("Expected AuthenticationError, got %v", err)
+	}
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("Expected Error to match ErrAuthentication")
+	}
+
+	_, err = client.Charges.Retrieve("ch_502")
+	stripeErr := &Error{}
+	if !errors.As(err, &stripeErr) {
+		t.Errorf("Expected Stripe Error, got %v", err)
+		return
+	}
+	if stripeErr.Detail.Type != ErrTypeAPI || !strings.Contains(stripeErr.Error(), "Bad Gateway") {
+		t.Errorf("Expected api_error describing the response, got %s: %s", stripeErr.Detail.Type, stripeErr.Error())
+	}
+}
@@ -0,0 +1,59 @@
+package stripe
+
+import (
+	"encoding/json"
+)
+
+// Expandable is a field that holds the ID of a related object or, when the
+// field was expanded using the Expand option, the fully decoded object.
+//
+// see https://stripe.com/docs/api#expand
+type Expandable[T any] struct {
+	// The ID of the related object. Always set, even when expanded.
+	Id string
+
+	// The related object, or nil if the field was not expanded.
+	Object *T
+}
+
+// Expanded returns true if the field holds the fully decoded object.
+func (self Expandable[T]) Expanded() bool {
+	return self.Object != nil
+}
+
+// String returns the ID of the related object.
+func (self Expandable[T]) String() string {
+	return self.Id
+}
+
+func (self *Expandable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	// a field that was not expanded is just the ID of the object
+	if len(data) != 0 && data[0] == '"' {
+		return json.Unmarshal(data, &self.Id)
+	}
+
+	// else the field holds the object itself, which also carries its ID
+	ref := struct {
+		Id string `json:"id"`
+	}{}
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return err
+	}
+	self.Id = ref.Id
+	self.Object = new(T)
+	return json.Unmarshal(data, self.Object)
+}
+
+func (self Expandable[T]) MarshalJSON() ([]byte, error) {
+	if self.Object != nil {
+		return json.Marshal(self.Object)
+	}
+	if self.Id == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(self.Id)
+}
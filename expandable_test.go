@@ -0,0 +1,78 @@
+package stripe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestExpand will test that expanded fields are requested with expand[], and
+// decoded into the full object, while other fields only hold the ID.
+func TestExpand(t *testing.T) {
+	expand := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expand = r.URL.Query()["expand[]"]
+		w.Write([]byte(`{"id":"ch_1","customer":{"id":"cus_1","email":"george@mail.com"},"invoice":"in_1"}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	charge, err := client.Charges.Retrieve("ch_1", Expand("customer"))
+	if err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+		return
+	}
+	if len(expand) != 1 || expand[0] != "customer" {
+		t.Errorf("Expected expand[]=customer, got %v", expand)
+	}
+	if !charge.Customer.Expanded() || charge.Customer.Id != "cus_1" {
+		t.Errorf("Expected expanded Customer cus_1, got %+v", charge.Customer)
+		return
+	}
+	if charge.Customer.Object.Email != "george@mail.com" {
+		t.Errorf("Expected Customer Email george@mail.com, got %s", charge.Customer.Object.Email)
+	}
+	if charge.Invoice.Expanded() || charge.Invoice.Id != "in_1" {
+		t.Errorf("Expected Invoice ID in_1, got %+v", charge.Invoice)
+	}
+}
+
+// TestExpandCopiesValues will test that the Expand option never writes into
+// the caller's url.Values, even when a value has spare capacity.
+func TestExpandCopiesValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	vals := make([]string, 1, 4)
+	vals[0] = "customer"
+	values := url.Values{"expand[]": vals}
+	client.Call("GET", "/v1/charges/ch_1", values, nil, Expand("invoice"))
+
+	if len(values["expand[]"]) != 1 || vals[:2][1] != "" {
+		t.Errorf("Expected caller values untouched, got %v", vals[:2])
+	}
+}
+
+func TestExpandableJSON(t *testing.T) {
+	charge := Charge{}
+	if err := json.Unmarshal([]byte(`{"customer":null,"invoice":"in_1"}`), &charge); err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+	}
+	if charge.Customer.Id != "" || charge.Customer.Expanded() {
+		t.Errorf("Expected empty Customer, got %+v", charge.Customer)
+	}
+
+	data, _ := json.Marshal(charge.Invoice)
+	if string(data) != `"in_1"` {
+		t.Errorf("Expected Invoice to encode as its ID, got %s", data)
+	}
+}
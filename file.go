@@ -0,0 +1,186 @@
+package stripe
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"sort"
+)
+
+// File Purposes
+const (
+	FilePurposeDisputeEvidence  = "dispute_evidence"
+	FilePurposeIdentityDocument = "identity_document"
+)
+
+// File represents a file uploaded to Stripe, ie a receipt submitted as
+// dispute evidence. Its ID can be used wherever Stripe expects a file, ie
+// DisputeEvidence.Receipt.
+//
+// see https://stripe.com/docs/api#file_object
+type File struct {
+	Id      string `json:"id"`
+	Purpose string `json:"purpose"`
+	Size    int64  `json:"size"`
+	Type    string `json:"type"`
+	Url     String `json:"url"`
+	Created int64  `json:"created"`
+}
+
+// FileParams encapsulates options for uploading a File.
+type FileParams struct {
+	// The purpose of the file, one of the File Purposes.
+	Purpose string `form:"purpose"`
+
+	// The name of the file, ie receipt.pdf.
+	Name string `form:"-"`
+
+	// The contents of the file, which is streamed to Stripe as it is read.
+	File io.Reader `form:"-"`
+}
+
+// FileListParams encapsulates options for listing Files.
+type FileListParams struct {
+	ListParams
+
+	// (Optional) Only return files with this purpose.
+	Purpose string `form:"purpose,omitempty"`
+}
+
+// FileClient encapsulates operations for uploading and querying files using
+// the Stripe REST API.
+type FileClient struct {
+	client *Client
+}
+
+// Uploads a new File. Since the contents of the file are streamed, the
+// upload is never retried.
+//
+// see https://stripe.com/docs/api#create_file_upload
+func (self *FileClient) Create(params *FileParams, opts ...RequestOption) (*File, error) {
+	return self.CreateContext(context.Background(), params, opts...)
+}
+
+// CreateContext is the context-aware version of Create.
+func (self *FileClient) CreateContext(ctx context.Context, params *FileParams, opts ...RequestOption) (*File, error) {
+	file := File{}
+	if params == nil || params.File == nil {
+		errs := fieldErrors{}
+		errs.add("file", "is required")
+		return &file, errs.err()
+	}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+
+	err := self.client.upload(ctx, "/v1/files", values, params.Name, params.File, &file, opts...)
+	return &file, err
+}
+
+// Retrieves the File with the given ID.
+//
+// see https://stripe.com/docs/api#retrieve_file_upload
+func (self *FileClient) Retrieve(id string, opts ...RequestOption) (*File, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *FileClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*File, error) {
+	file := File{}
+	path := "/v1/files/" + url.QueryEscape(id)
+	err := self.client.query(ctx, "GET", path, nil, &file, opts...)
+	return &file, err
+}
+
+// Page returns a single page of your Files, filtered by the given params.
+// The returned List reports whether more Files are available.
+//
+// see https://stripe.com/docs/api#list_file_uploads
+func (self *FileClient) Page(params *FileListParams, opts ...RequestOption) (*List[*File], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *FileClient) PageContext(ctx context.Context, params *FileListParams, opts ...RequestOption) (*List[*File], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all of your Files, filtered by the given
+// params, which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_file_uploads
+func (self *FileClient) Iter(params *FileListParams, opts ...RequestOption) *Iter[*File] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *FileClient) IterContext(ctx context.Context, params *FileListParams, opts ...RequestOption) *Iter[*File] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*File], error) {
+		return self.page(ctx, values, opts...)
+	}, func(f *File) string { return f.Id })
+}
+
+func (self *FileClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*File], error) {
+	list := List[*File]{}
+	err := self.client.query(ctx, "GET", "/v1/files", values, &list, opts...)
+	return &list, err
+}
+
+// upload is a file sent as the "file" part of a multipart/form-data request
+// body, alongside the request parameters.
+type upload struct {
+	name string
+	file io.Reader
+}
+
+// upload submits a multipart/form-data POST request to the Stripe upload URL,
+// streaming the file, and parses the JSON-encoded http.Response into the
+// value pointed to by v.
+func (self *Client) upload(ctx context.Context, path string, values url.Values, name string, file io.Reader, v interface{}, opts ...RequestOption) error {
+	// copy the options, so that the caller's slice is left untouched
+	opts = append(opts[:len(opts):len(opts)], func(o *requestOptions) {
+		o.upload = &upload{name: name, file: file}
+	})
+	return self.query(ctx, "POST", path, values, v, opts...)
+}
+
+// stream returns a multipart/form-data request body, and its content type,
+// which writes the parameters and the file as the body is read. The body is
+// closed by the http.Client once the request is sent, or fails, which stops
+// the writer.
+func (self *upload) stream(values url.Values) (io.Reader, string) {
+	r, w := io.Pipe()
+	mw := multipart.NewWriter(w)
+
+	go func() {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			for _, val := range values[key] {
+				if err := mw.WriteField(key, val); err != nil {
+					w.CloseWithError(err)
+					return
+				}
+			}
+		}
+
+		part, err := mw.CreateFormFile("file", self.name)
+		if err == nil {
+			_, err = io.Copy(part, self.file)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		w.CloseWithError(err)
+	}()
+
+	return r, mw.FormDataContentType()
+}
@@ -0,0 +1,120 @@
+package stripe
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCreateFile will test that a file is streamed to the upload URL as
+// multipart/form-data, alongside its purpose.
+func TestCreateFile(t *testing.T) {
+	purpose, name, contents := "", "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/files" {
+			t.Errorf("Expected path /v1/files, got %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Expected multipart form, got Error %s", err.Error())
+			return
+		}
+		purpose = r.FormValue("purpose")
+		if file, header, err := r.FormFile("file"); err == nil {
+			data, _ := io.ReadAll(file)
+			name, contents = header.Filename, string(data)
+		}
+		w.Write([]byte(`{"id":"file_1","purpose":"dispute_evidence","size":12,"type":"pdf"}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = "http://api.invalid"
+	client.UploadUrl = server.URL
+
+	params := FileParams{
+		Purpose: FilePurposeDisputeEvidence,
+		Name:    "receipt.pdf",
+		File:    strings.NewReader("%PDF-1.4 ..."),
+	}
+	file, err := client.Files.Create(&params)
+	if err != nil {
+		t.Errorf("Expected File, got Error %s", err.Error())
+		return
+	}
+	if file.Id != "file_1" || file.Type != "pdf" {
+		t.Errorf("Expected pdf File file_1, got %+v", file)
+	}
+	if purpose != FilePurposeDisputeEvidence {
+		t.Errorf("Expected purpose %s, got %s", FilePurposeDisputeEvidence, purpose)
+	}
+	if name != "receipt.pdf" || contents != "%PDF-1.4 ..." {
+		t.Errorf("Expected file receipt.pdf, got %s %q", name, contents)
+	}
+}
+
+// TestCreateFileNoRetry will test that a failed upload is never retried,
+// since the file cannot be read twice.
+func TestCreateFileNoRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server)
+	client.UploadUrl = server.URL
+
+	params := FileParams{Purpose: FilePurposeDisputeEvidence, File: strings.NewReader("...")}
+	if _, err := client.Files.Create(&params, IdempotencyKey("upload-1")); err == nil {
+		t.Errorf("Expected Error, got nil")
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+// TestCreateFileNilParams will test that an upload without params returns a
+// ValidationError instead of making a request.
+func TestCreateFileNilParams(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.UploadUrl = server.URL
+
+	_, err := client.Files.Create(nil)
+	if invalid, ok := err.(*ValidationError); !ok || invalid.Fields[0].Param != "file" {
+		t.Errorf("Expected ValidationError for file, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+}
+
+// TestCreateFileNilFile will test that an upload without a File returns a
+// ValidationError instead of making a request.
+func TestCreateFileNilFile(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.UploadUrl = server.URL
+
+	params := FileParams{Purpose: FilePurposeDisputeEvidence, Name: "receipt.pdf"}
+	_, err := client.Files.Create(&params)
+	if invalid, ok := err.(*ValidationError); !ok || invalid.Fields[0].Param != "file" {
+		t.Errorf("Expected ValidationError for file, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+}
@@ -0,0 +1,135 @@
+package stripe
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// appendParamsToValues encodes the fields of a Params struct as url.Values,
+// using the name given by each field's `form` struct tag:
+//
+//	Name     string            `form:"name"`           // always sent
+//	Email    string            `form:"email,omitempty"` // omitted if zero
+//	Card     *CardParams       `form:"card,omitempty"`  // card[number]=...
+//	Metadata map[string]string `form:"metadata"`        // metadata[key]=...
+//	Expand   []string          `form:"expand"`          // expand[]=...
+//	Internal string            `form:"-"`               // never sent
+//
+// An Optional field is only sent if it was set (even to a zero value), and is
+// sent as an empty value, clearing the field, if it was set to null.
+//
+// Fields without a `form` tag are never sent, except for embedded structs,
+// whose fields are encoded as if they belonged to the outer struct. A
+// time.Time is sent as a UTC integer timestamp.
+func appendParamsToValues(params interface{}, values *url.Values) {
+	appendValue("", reflect.ValueOf(params), false, values)
+}
+
+// appendNestedParamsToValues encodes the fields of a Params struct as the
+// fields of an object with the given name, ie card[number].
+func appendNestedParamsToValues(name string, params interface{}, values *url.Values) {
+	appendValue(name, reflect.ValueOf(params), false, values)
+}
+
+func appendValue(name string, v reflect.Value, omitempty bool, values *url.Values) {
+	// Optional parameters are only sent if they were set, and are sent
+	// as an empty value if they were set to null.
+	if v.IsValid() && v.CanInterface() {
+		if param, ok := v.Interface().(optionalParam); ok {
+			switch value, state := param.formValue(); state {
+			case optionalSet:
+				appendValue(name, value, false, values)
+			case optionalNull:
+				values.Add(name, "")
+			}
+			return
+		}
+	}
+
+	// nil pointers and interfaces are never sent
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || (omitempty && v.IsZero()) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			values.Add(name, strconv.FormatInt(t.Unix(), 10))
+			return
+		}
+		appendStruct(name, v, values)
+	case reflect.Map:
+		// sort the keys, so that the encoding is deterministic
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			appendValue(nestedName(name, fmt.Sprint(key)), v.MapIndex(key), false, values)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			// lists of objects are indexed, ie lines[0][amount], while
+			// lists of simple values are not, ie expand[]
+			elem := v.Index(i)
+			if reflect.Indirect(elem).Kind() == reflect.Struct {
+				appendValue(nestedName(name, strconv.Itoa(i)), elem, false, values)
+			} else {
+				appendValue(name+"[]", elem, false, values)
+			}
+		}
+	case reflect.Bool:
+		values.Add(name, strconv.FormatBool(v.Bool()))
+	case reflect.String:
+		values.Add(name, v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		values.Add(name, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		values.Add(name, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		values.Add(name, strconv.FormatFloat(v.Float(), 'f', -1, 64))
+	default:
+		panic("stripe: cannot encode parameter " + name + " of type " + v.Type().String())
+	}
+}
+
+func appendStruct(name string, v reflect.Value, values *url.Values) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("form")
+
+		switch {
+		case tag == "-":
+			continue
+		case !ok && field.Anonymous:
+			// embedded structs (ie ListParams) are flattened
+			appendValue(name, v.Field(i), false, values)
+			continue
+		case !ok || !field.IsExported():
+			continue
+		}
+
+		key, opts, _ := strings.Cut(tag, ",")
+		appendValue(nestedName(name, key), v.Field(i), opts == "omitempty", values)
+	}
+}
+
+// nestedName returns the name of a field of the named object.
+func nestedName(name, field string) string {
+	if name == "" {
+		return field
+	}
+	return name + "[" + field + "]"
+}
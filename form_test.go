@@ -0,0 +1,126 @@
+package stripe
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAppendParamsToValues(t *testing.T) {
+	params := CustomerParams{
+		Email: Value("george.costanza@mail.com"),
+		Card: &CardParams{
+			Number:   "4242424242424242",
+			ExpMonth: 5,
+			ExpYear:  2030,
+		},
+		AccountBalance: Value[int64](-400),
+		Metadata:       map[string]string{"order": "6735", "cart": "42"},
+	}
+	values := url.Values{}
+	appendParamsToValues(&params, &values)
+
+	want := "account_balance=-400&card%5Bexp_month%5D=5&card%5Bexp_year%5D=2030" +
+		"&card%5Bnumber%5D=4242424242424242&email=george.costanza%40mail.com" +
+		"&metadata%5Bcart%5D=42&metadata%5Border%5D=6735"
+	if got := values.Encode(); got != want {
+		t.Errorf("Expected values\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestAppendParamsToValuesTypes(t *testing.T) {
+	type line struct {
+		Amount int64 `form:"amount"`
+	}
+	params := struct {
+		ChargeListParams
+		Expand  []string  `form:"expand"`
+		Lines   []line    `form:"lines"`
+		Date    time.Time `form:"date"`
+		Paid    bool      `form:"paid"`
+		Skipped string    `form:"-"`
+		Plain   string
+	}{
+		ChargeListParams: ChargeListParams{
+			ListParams: ListParams{Limit: 3, Created: &RangeQuery{Gt: 10}},
+		},
+		Expand:  []string{"customer", "invoice"},
+		Lines:   []line{{400}, {500}},
+		Date:    time.Unix(1380000000, 0),
+		Skipped: "skipped",
+		Plain:   "plain",
+	}
+	values := url.Values{}
+	appendParamsToValues(&params, &values)
+
+	want := url.Values{
+		"limit":            {"3"},
+		"created[gt]":      {"10"},
+		"expand[]":         {"customer", "invoice"},
+		"lines[0][amount]": {"400"},
+		"lines[1][amount]": {"500"},
+		"date":             {"1380000000"},
+		"paid":             {"false"},
+	}
+	if values.Encode() != want.Encode() {
+		t.Errorf("Expected values\n%s\ngot\n%s", want.Encode(), values.Encode())
+	}
+}
+
+// TestChargeParamsCardPrecedence will test that a charge sends its Card over
+// its Token, and only sends the Customer when neither is given.
+func TestChargeParamsCardPrecedence(t *testing.T) {
+	card := &CardParams{Number: "4242424242424242", ExpMonth: 5, ExpYear: 2030}
+	tests := []struct {
+		params ChargeParams
+		want   string
+	}{
+		{ChargeParams{Customer: "cus_1", Card: card, Token: "tok_1"},
+			"card%5Bexp_month%5D=5&card%5Bexp_year%5D=2030&card%5Bnumber%5D=4242424242424242"},
+		{ChargeParams{Customer: "cus_1", Token: "tok_1"}, "card=tok_1"},
+		{ChargeParams{Customer: "cus_1"}, "customer=cus_1"},
+	}
+	for _, test := range tests {
+		values := url.Values{}
+		test.params.appendTo(&values)
+		delete(values, "amount")
+		delete(values, "currency")
+		delete(values, "description")
+		if got := values.Encode(); got != test.want {
+			t.Errorf("Expected values %s, got %s", test.want, got)
+		}
+	}
+}
+
+// TestCustomerParamsCardPrecedence will test that a customer sends its Card
+// over its Token.
+func TestCustomerParamsCardPrecedence(t *testing.T) {
+	params := CustomerParams{
+		Card:  &CardParams{Number: "4242424242424242", ExpMonth: 5, ExpYear: 2030},
+		Token: "tok_1",
+	}
+	values := url.Values{}
+	params.appendTo(&values)
+
+	want := "card%5Bexp_month%5D=5&card%5Bexp_year%5D=2030&card%5Bnumber%5D=4242424242424242"
+	if got := values.Encode(); got != want {
+		t.Errorf("Expected values %s, got %s", want, got)
+	}
+}
+
+// TestSubscriptionParamsCardPrecedence will test that a subscription sends
+// its Token over its Card.
+func TestSubscriptionParamsCardPrecedence(t *testing.T) {
+	params := SubscriptionParams{
+		Plan:  "gold",
+		Card:  &CardParams{Number: "4242424242424242", ExpMonth: 5, ExpYear: 2030},
+		Token: "tok_1",
+	}
+	values := url.Values{}
+	params.appendTo(&values)
+
+	want := "card=tok_1&plan=gold"
+	if got := values.Encode(); got != want {
+		t.Errorf("Expected values %s, got %s", want, got)
+	}
+}
@@ -1,6 +1,7 @@
 package stripe
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 )
@@ -11,25 +12,25 @@ import (
 //
 // see https://stripe.com/docs/api#invoice_object
 type Invoice struct {
-	Id              string        `json:"id"`
-	AmountDue       int64         `json:"amount_due"`
-	AttemptCount    int           `json:"attempt_count"`
-	Attempted       bool          `json:"attempted"`
-	Closed          bool          `json:"closed"`
-	Paid            bool          `json:"paid"`
-	PeriodEnd       int64         `json:"period_end"`
-	PeriodStart     int64         `json:"period_start"`
-	Subtotal        int64         `json:"subtotal"`
-	Total           int64         `json:"total"`
-	Charge          String        `json:"charge"`
-	Customer        string        `json:"customer"`
-	Date            int64         `json:"date"`
-	Discount        *Discount     `json:"discount"`
-	Lines           *InvoiceLines `json:"lines"`
-	StartingBalance int64         `json:"starting_balance"`
-	EndingBalance   Int64         `json:"ending_balance"`
-	NextPayment     Int64         `json:"next_payment_attempt"`
-	Livemode        bool          `json:"livemode"`
+	Id              string               `json:"id"`
+	AmountDue       int64                `json:"amount_due"`
+	AttemptCount    int                  `json:"attempt_count"`
+	Attempted       bool                 `json:"attempted"`
+	Closed          bool                 `json:"closed"`
+	Paid            bool                 `json:"paid"`
+	PeriodEnd       int64                `json:"period_end"`
+	PeriodStart     int64                `json:"period_start"`
+	Subtotal        int64                `json:"subtotal"`
+	Total           int64                `json:"total"`
+	Charge          Expandable[Charge]   `json:"charge"`
+	Customer        Expandable[Customer] `json:"customer"`
+	Date            int64                `json:"date"`
+	Discount        *Discount            `json:"discount"`
+	Lines           *InvoiceLines        `json:"lines"`
+	StartingBalance int64                `json:"starting_balance"`
+	EndingBalance   Int64                `json:"ending_balance"`
+	NextPayment     Int64                `json:"next_payment_attempt"`
+	Livemode        bool                 `json:"livemode"`
 }
 
 // InvoiceLines represents an individual line items that is part of an invoice.
@@ -50,59 +51,141 @@ type Period struct {
 	End   int64 `json:"end"`
 }
 
+// InvoiceListParams encapsulates options for listing Invoices.
+type InvoiceListParams struct {
+	ListParams
+
+	// (Optional) Only return invoices for the customer with this ID.
+	Customer string `form:"customer,omitempty"`
+
+	// (Optional) Only return invoices dated within the given range. Note that
+	// invoices are filtered by date, rather than by ListParams.Created.
+	Date *RangeQuery `form:"date,omitempty"`
+}
+
 // InvoiceClient encapsulates operations for querying invoices using the Stripe
 // REST API.
-type InvoiceClient struct{}
+type InvoiceClient struct {
+	client *Client
+}
 
 // Retrieves the invoice with the given ID.
 //
 // see https://stripe.com/docs/api#retrieve_invoice
-func (self *InvoiceClient) Retrieve(id string) (*Invoice, error) {
+func (self *InvoiceClient) Retrieve(id string, opts ...RequestOption) (*Invoice, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *InvoiceClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*Invoice, error) {
 	invoice := Invoice{}
 	path := "/v1/invoices/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &invoice)
+	err := self.client.query(ctx, "GET", path, nil, &invoice, opts...)
 	return &invoice, err
 }
 
 // Retrieves the upcoming invoice the given customer ID.
 //
 // see https://stripe.com/docs/api#retrieve_customer_invoice
-func (self *InvoiceClient) RetrieveCustomer(cid string) (*Invoice, error) {
+func (self *InvoiceClient) RetrieveCustomer(cid string, opts ...RequestOption) (*Invoice, error) {
+	return self.RetrieveCustomerContext(context.Background(), cid, opts...)
+}
+
+// RetrieveCustomerContext is the context-aware version of RetrieveCustomer.
+func (self *InvoiceClient) RetrieveCustomerContext(ctx context.Context, cid string, opts ...RequestOption) (*Invoice, error) {
 	invoice := Invoice{}
 	values := url.Values{"customer": {cid}}
-	err := query("GET", "/v1/invoices/upcoming", values, &invoice)
+	err := self.client.query(ctx, "GET", "/v1/invoices/upcoming", values, &invoice, opts...)
 	return &invoice, err
 }
 
 // Returns a list of Invoices.
 //
 // see https://stripe.com/docs/api#list_customer_invoices
-func (self *InvoiceClient) List() ([]*Invoice, error) {
-	return self.list("", 10, 0)
+func (self *InvoiceClient) List(opts ...RequestOption) ([]*Invoice, error) {
+	return self.ListContext(context.Background(), opts...)
+}
+
+// ListContext is the context-aware version of List.
+func (self *InvoiceClient) ListContext(ctx context.Context, opts ...RequestOption) ([]*Invoice, error) {
+	return self.list(ctx, "", 10, 0, opts...)
 }
 
 // Returns a list of Invoices at the specified range.
 //
 // see https://stripe.com/docs/api#list_customer_invoices
-func (self *InvoiceClient) ListN(count int, offset int) ([]*Invoice, error) {
-	return self.list("", count, offset)
+func (self *InvoiceClient) ListN(count int, offset int, opts ...RequestOption) ([]*Invoice, error) {
+	return self.ListNContext(context.Background(), count, offset, opts...)
+}
+
+// ListNContext is the context-aware version of ListN.
+func (self *InvoiceClient) ListNContext(ctx context.Context, count int, offset int, opts ...RequestOption) ([]*Invoice, error) {
+	return self.list(ctx, "", count, offset, opts...)
 }
 
 // Returns a list of Invoices with the given Customer ID.
 //
 // see https://stripe.com/docs/api#list_customer_invoices
-func (self *InvoiceClient) CustomerList(id string) ([]*Invoice, error) {
-	return self.list(id, 10, 0)
+func (self *InvoiceClient) CustomerList(id string, opts ...RequestOption) ([]*Invoice, error) {
+	return self.CustomerListContext(context.Background(), id, opts...)
+}
+
+// CustomerListContext is the context-aware version of CustomerList.
+func (self *InvoiceClient) CustomerListContext(ctx context.Context, id string, opts ...RequestOption) ([]*Invoice, error) {
+	return self.list(ctx, id, 10, 0, opts...)
 }
 
 // Returns a list of Invoices with the given Customer ID, at the specified range.
 //
 // see https://stripe.com/docs/api#list_customer_invoices
-func (self *InvoiceClient) CustomerListN(id string, count int, offset int) ([]*Invoice, error) {
-	return self.list(id, count, offset)
+func (self *InvoiceClient) CustomerListN(id string, count int, offset int, opts ...RequestOption) ([]*Invoice, error) {
+	return self.CustomerListNContext(context.Background(), id, count, offset, opts...)
+}
+
+// CustomerListNContext is the context-aware version of CustomerListN.
+func (self *InvoiceClient) CustomerListNContext(ctx context.Context, id string, count int, offset int, opts ...RequestOption) ([]*Invoice, error) {
+	return self.list(ctx, id, count, offset, opts...)
+}
+
+// Page returns a single page of Invoices, filtered by the given params.
+// The returned List reports whether more Invoices are available.
+//
+// see https://stripe.com/docs/api#list_customer_invoices
+func (self *InvoiceClient) Page(params *InvoiceListParams, opts ...RequestOption) (*List[*Invoice], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *InvoiceClient) PageContext(ctx context.Context, params *InvoiceListParams, opts ...RequestOption) (*List[*Invoice], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all Invoices, filtered by the given params,
+// which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_customer_invoices
+func (self *InvoiceClient) Iter(params *InvoiceListParams, opts ...RequestOption) *Iter[*Invoice] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *InvoiceClient) IterContext(ctx context.Context, params *InvoiceListParams, opts ...RequestOption) *Iter[*Invoice] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Invoice], error) {
+		return self.page(ctx, values, opts...)
+	}, func(i *Invoice) string { return i.Id })
+}
+
+func (self *InvoiceClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*Invoice], error) {
+	list := List[*Invoice]{}
+	err := self.client.query(ctx, "GET", "/v1/invoices", values, &list, opts...)
+	return &list, err
 }
 
-func (self *InvoiceClient) list(id string, count int, offset int) ([]*Invoice, error) {
+func (self *InvoiceClient) list(ctx context.Context, id string, count int, offset int, opts ...RequestOption) ([]*Invoice, error) {
 	// define a wrapper function for the Invoice List, so that we can
 	// cleanly parse the JSON
 	type listInvoicesResp struct{ Data []*Invoice }
@@ -119,7 +202,7 @@ func (self *InvoiceClient) list(id string, count int, offset int) ([]*Invoice, e
 		values.Add("customer", id)
 	}
 
-	err := query("GET", "/v1/invoices", values, &resp)
+	err := self.client.query(ctx, "GET", "/v1/invoices", values, &resp, opts...)
 	if err != nil {
 		return nil, err
 	}
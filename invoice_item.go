@@ -1,6 +1,7 @@
 package stripe
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 )
@@ -10,74 +11,83 @@ import (
 //
 // see https://stripe.com/docs/api#invoiceitem_object
 type InvoiceItem struct {
-	Id       string `json:"id"`
-	Amount   int64  `json:"amount"`
-	Currency string `json:"currency"`
-	Customer string `json:"customer"`
-	Date     int64  `json:"date"`
-	Desc     String `json:"description"`
-	Invoice  String `json:"invoice"`
-	Livemode bool   `json:"livemode"`
+	Id       string               `json:"id"`
+	Amount   int64                `json:"amount"`
+	Currency string               `json:"currency"`
+	Customer Expandable[Customer] `json:"customer"`
+	Date     int64                `json:"date"`
+	Desc     String               `json:"description"`
+	Invoice  Expandable[Invoice]  `json:"invoice"`
+	Livemode bool                 `json:"livemode"`
 }
 
 // InvoiceItemParams encapsulates options for creating a new Invoice Items.
 type InvoiceItemParams struct {
 	// The ID of the customer who will be billed when this invoice item is
 	// billed.
-	Customer string
+	Customer string `form:"customer"`
 
 	// The integer amount in cents of the charge to be applied to the upcoming
 	// invoice. If you want to apply a credit to the customer's account, pass a
 	// negative amount.
-	Amount int64
+	Amount int64 `form:"amount"`
 
 	// 3-letter ISO code for currency. Currently, only 'usd' is supported.
-	Currency string
+	Currency string `form:"currency"`
 
 	// (Optional) An arbitrary string which you can attach to the invoice item.
 	// The description is displayed in the invoice for easy tracking.
-	Desc string
+	Desc Optional[string] `form:"description"`
 
 	// (Optional) The ID of an existing invoice to add this invoice item to.
 	// When left blank, the invoice item will be added to the next upcoming
 	// scheduled invoice.
-	Invoice string
+	Invoice string `form:"invoice,omitempty"`
+}
+
+// InvoiceItemListParams encapsulates options for listing Invoice Items.
+type InvoiceItemListParams struct {
+	ListParams
+
+	// (Optional) Only return invoice items for the customer with this ID.
+	Customer string `form:"customer,omitempty"`
 }
 
 // InvoiceItemClient encapsulates operations for creating, updating, deleting
 // and querying invoices using the Stripe REST API.
-type InvoiceItemClient struct{}
+type InvoiceItemClient struct {
+	client *Client
+}
 
 // Create adds an arbitrary charge or credit to the customer's upcoming invoice.
 //
 // see https://stripe.com/docs/api#invoiceitem_object
-func (self *InvoiceItemClient) Create(params *InvoiceItemParams) (*InvoiceItem, error) {
-	item := InvoiceItem{}
-	values := url.Values{
-		"amount":   {strconv.FormatInt(params.Amount, 10)},
-		"currency": {params.Currency},
-		"customer": {params.Customer},
-	}
+func (self *InvoiceItemClient) Create(params *InvoiceItemParams, opts ...RequestOption) (*InvoiceItem, error) {
+	return self.CreateContext(context.Background(), params, opts...)
+}
 
-	// add optional parameters
-	if len(params.Desc) != 0 {
-		values.Add("description", params.Desc)
-	}
-	if len(params.Invoice) != 0 {
-		values.Add("invoice", params.Invoice)
-	}
+// CreateContext is the context-aware version of Create.
+func (self *InvoiceItemClient) CreateContext(ctx context.Context, params *InvoiceItemParams, opts ...RequestOption) (*InvoiceItem, error) {
+	item := InvoiceItem{}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
 
-	err := query("POST", "/v1/invoiceitems", values, &item)
+	err := self.client.query(ctx, "POST", "/v1/invoiceitems", values, &item, opts...)
 	return &item, err
 }
 
 // Retrieves the Invoice Item with the given ID.
 //
 // see https://stripe.com/docs/api#retrieve_invoiceitem
-func (self *InvoiceItemClient) Retrieve(id string) (*InvoiceItem, error) {
+func (self *InvoiceItemClient) Retrieve(id string, opts ...RequestOption) (*InvoiceItem, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *InvoiceItemClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*InvoiceItem, error) {
 	item := InvoiceItem{}
 	path := "/v1/invoiceitems/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &item)
+	err := self.client.query(ctx, "GET", path, nil, &item, opts...)
 	return &item, err
 }
 
@@ -85,28 +95,38 @@ func (self *InvoiceItemClient) Retrieve(id string) (*InvoiceItem, error) {
 // invoice, using the given Invoice Item ID.
 //
 // see https://stripe.com/docs/api#update_invoiceitem
-func (self *InvoiceItemClient) Update(id string, params *InvoiceItemParams) (*InvoiceItem, error) {
+func (self *InvoiceItemClient) Update(id string, params *InvoiceItemParams, opts ...RequestOption) (*InvoiceItem, error) {
+	return self.UpdateContext(context.Background(), id, params, opts...)
+}
+
+// UpdateContext is the context-aware version of Update.
+func (self *InvoiceItemClient) UpdateContext(ctx context.Context, id string, params *InvoiceItemParams, opts ...RequestOption) (*InvoiceItem, error) {
 	item := InvoiceItem{}
 	values := url.Values{}
 
-	if len(params.Desc) != 0 {
-		values.Add("description", params.Desc)
-	}
-	if params.Amount != 0 {
-		values.Add("invoice", strconv.FormatInt(params.Amount, 10))
-	}
+	// only the amount and description of an invoice item can be updated
+	update := struct {
+		Amount int64            `form:"amount,omitempty"`
+		Desc   Optional[string] `form:"description"`
+	}{params.Amount, params.Desc}
+	appendParamsToValues(&update, &values)
 
-	err := query("POST", "/v1/invoiceitems/"+url.QueryEscape(id), values, &item)
+	err := self.client.query(ctx, "POST", "/v1/invoiceitems/"+url.QueryEscape(id), values, &item, opts...)
 	return &item, err
 }
 
 // Removes an Invoice Item with the given ID.
 //
 // see https://stripe.com/docs/api#delete_invoiceitem
-func (self *InvoiceItemClient) Delete(id string) (bool, error) {
+func (self *InvoiceItemClient) Delete(id string, opts ...RequestOption) (bool, error) {
+	return self.DeleteContext(context.Background(), id, opts...)
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (self *InvoiceItemClient) DeleteContext(ctx context.Context, id string, opts ...RequestOption) (bool, error) {
 	resp := DeleteResp{}
 	path := "/v1/invoiceitems/" + url.QueryEscape(id)
-	if err := query("DELETE", path, nil, &resp); err != nil {
+	if err := self.client.query(ctx, "DELETE", path, nil, &resp, opts...); err != nil {
 		return false, err
 	}
 	return resp.Deleted, nil
@@ -115,33 +135,91 @@ func (self *InvoiceItemClient) Delete(id string) (bool, error) {
 // Returns a list of Invoice Items.
 //
 // see https://stripe.com/docs/api#list_invoiceitems
-func (self *InvoiceItemClient) List() ([]*InvoiceItem, error) {
-	return self.list("", 10, 0)
+func (self *InvoiceItemClient) List(opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.ListContext(context.Background(), opts...)
+}
+
+// ListContext is the context-aware version of List.
+func (self *InvoiceItemClient) ListContext(ctx context.Context, opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.list(ctx, "", 10, 0, opts...)
 }
 
 // Returns a list of Invoice Items at the specified range.
 //
 // see https://stripe.com/docs/api#list_invoiceitems
-func (self *InvoiceItemClient) ListN(count int, offset int) ([]*InvoiceItem, error) {
-	return self.list("", count, offset)
+func (self *InvoiceItemClient) ListN(count int, offset int, opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.ListNContext(context.Background(), count, offset, opts...)
+}
+
+// ListNContext is the context-aware version of ListN.
+func (self *InvoiceItemClient) ListNContext(ctx context.Context, count int, offset int, opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.list(ctx, "", count, offset, opts...)
 }
 
 // Returns a list of Invoice Items for the specified Customer ID.
 //
 // see https://stripe.com/docs/api#list_invoiceitems
-func (self *InvoiceItemClient) CustomerList(id string) ([]*InvoiceItem, error) {
-	return self.list(id, 10, 0)
+func (self *InvoiceItemClient) CustomerList(id string, opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.CustomerListContext(context.Background(), id, opts...)
+}
+
+// CustomerListContext is the context-aware version of CustomerList.
+func (self *InvoiceItemClient) CustomerListContext(ctx context.Context, id string, opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.list(ctx, id, 10, 0, opts...)
 }
 
 // Returns a list of Invoice Items for the specified Customer ID, at the
 // specified range.
 //
 // see https://stripe.com/docs/api#list_invoiceitems
-func (self *InvoiceItemClient) CustomerListN(id string, count int, offset int) ([]*InvoiceItem, error) {
-	return self.list(id, count, offset)
+func (self *InvoiceItemClient) CustomerListN(id string, count int, offset int, opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.CustomerListNContext(context.Background(), id, count, offset, opts...)
+}
+
+// CustomerListNContext is the context-aware version of CustomerListN.
+func (self *InvoiceItemClient) CustomerListNContext(ctx context.Context, id string, count int, offset int, opts ...RequestOption) ([]*InvoiceItem, error) {
+	return self.list(ctx, id, count, offset, opts...)
+}
+
+// Page returns a single page of Invoice Items, filtered by the given params.
+// The returned List reports whether more Invoice Items are available.
+//
+// see https://stripe.com/docs/api#list_invoiceitems
+func (self *InvoiceItemClient) Page(params *InvoiceItemListParams, opts ...RequestOption) (*List[*InvoiceItem], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *InvoiceItemClient) PageContext(ctx context.Context, params *InvoiceItemListParams, opts ...RequestOption) (*List[*InvoiceItem], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all Invoice Items, filtered by the given params,
+// which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_invoiceitems
+func (self *InvoiceItemClient) Iter(params *InvoiceItemListParams, opts ...RequestOption) *Iter[*InvoiceItem] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *InvoiceItemClient) IterContext(ctx context.Context, params *InvoiceItemListParams, opts ...RequestOption) *Iter[*InvoiceItem] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*InvoiceItem], error) {
+		return self.page(ctx, values, opts...)
+	}, func(i *InvoiceItem) string { return i.Id })
+}
+
+func (self *InvoiceItemClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*InvoiceItem], error) {
+	list := List[*InvoiceItem]{}
+	err := self.client.query(ctx, "GET", "/v1/invoiceitems", values, &list, opts...)
+	return &list, err
 }
 
-func (self *InvoiceItemClient) list(id string, count int, offset int) ([]*InvoiceItem, error) {
+func (self *InvoiceItemClient) list(ctx context.Context, id string, count int, offset int, opts ...RequestOption) ([]*InvoiceItem, error) {
 	// define a wrapper function for the Invoice Items List, so that we can
 	// cleanly parse the JSON
 	type listInvoiceItemsResp struct{ Data []*InvoiceItem }
@@ -158,7 +236,7 @@ func (self *InvoiceItemClient) list(id string, count int, offset int) ([]*Invoic
 		values.Add("customer", id)
 	}
 
-	err := query("GET", "/v1/invoiceitems", values, &resp)
+	err := self.client.query(ctx, "GET", "/v1/invoiceitems", values, &resp, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,159 @@
+package stripe
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+)
+
+// ListParams encapsulates options for paging through a list of objects.
+type ListParams struct {
+	// (Optional) A limit on the number of objects returned per page, between
+	// 1 and 100. The default is 10.
+	Limit int `form:"limit,omitempty"`
+
+	// (Optional) A cursor for pagination. Only objects created after (older
+	// than) the object with this ID are returned.
+	StartingAfter string `form:"starting_after,omitempty"`
+
+	// (Optional) A cursor for pagination. Only objects created before (newer
+	// than) the object with this ID are returned, and an Iter walks the list
+	// backwards, towards the most recently created object.
+	EndingBefore string `form:"ending_before,omitempty"`
+
+	// (Optional) Only return objects created within the given range.
+	Created *RangeQuery `form:"created,omitempty"`
+}
+
+// RangeQuery filters a list by a range of UTC integer timestamps. Zero fields
+// are ignored, so for example all charges created in March 2014 are:
+//
+//	&stripe.RangeQuery{
+//		Gte: time.Date(2014, 3, 1, 0, 0, 0, 0, time.UTC).Unix(),
+//		Lt:  time.Date(2014, 4, 1, 0, 0, 0, 0, time.UTC).Unix(),
+//	}
+type RangeQuery struct {
+	// Greater than the given timestamp.
+	Gt int64 `form:"gt,omitempty"`
+
+	// Greater than or equal to the given timestamp.
+	Gte int64 `form:"gte,omitempty"`
+
+	// Less than the given timestamp.
+	Lt int64 `form:"lt,omitempty"`
+
+	// Less than or equal to the given timestamp.
+	Lte int64 `form:"lte,omitempty"`
+}
+
+// List represents a single page of objects returned by the Stripe REST API.
+type List[T any] struct {
+	Object  string `json:"object"`
+	Url     string `json:"url"`
+	HasMore bool   `json:"has_more"`
+	Count   int    `json:"count"`
+	Data    []T    `json:"data"`
+}
+
+// UnmarshalJSON decodes a list object, or a plain array of objects, which
+// older API versions return for some lists (ie the refunds of a Charge).
+func (self *List[T]) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) != 0 && data[0] == '[' {
+		items := []T{}
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		*self = List[T]{Object: "list", Count: len(items), Data: items}
+		return nil
+	}
+
+	type list List[T]
+	return json.Unmarshal(data, (*list)(self))
+}
+
+// Iter lazily walks every object of a list, using the starting_after and
+// ending_before cursors to fetch the next page from Stripe only once the
+// current page has been consumed. Stopping early is simply a matter of no
+// longer calling Next.
+//
+//	iter := stripe.Charges.Iter(nil)
+//	for iter.Next() {
+//		charge := iter.Current()
+//		...
+//	}
+//	if err := iter.Err(); err != nil {
+//		...
+//	}
+type Iter[T any] struct {
+	fetch    func(url.Values) (*List[T], error)
+	id       func(T) string
+	values   url.Values
+	backward bool
+
+	page []T
+	cur  T
+	err  error
+	last bool
+}
+
+// newIter returns an Iter that fetches pages of objects matching the given
+// url.Values, and uses the id function to obtain the cursor for the next page.
+func newIter[T any](values url.Values, fetch func(url.Values) (*List[T], error), id func(T) string) *Iter[T] {
+	return &Iter[T]{
+		fetch:    fetch,
+		id:       id,
+		values:   values,
+		backward: values.Get("ending_before") != "",
+	}
+}
+
+// Next advances the iterator to the next object, fetching the next page from
+// Stripe if required. It returns false when the list is exhausted or an error
+// occurred.
+func (self *Iter[T]) Next() bool {
+	for len(self.page) == 0 {
+		if self.err != nil || self.last {
+			return false
+		}
+
+		list, err := self.fetch(self.values)
+		if err != nil {
+			self.err = err
+			return false
+		}
+		self.page = list.Data
+		self.last = !list.HasMore || len(list.Data) == 0
+
+		// when walking backwards, Stripe still returns each page newest
+		// first, so we reverse the page to keep moving away from the cursor.
+		if self.backward {
+			for i, j := 0, len(self.page)-1; i < j; i, j = i+1, j-1 {
+				self.page[i], self.page[j] = self.page[j], self.page[i]
+			}
+		}
+
+		// the last object of the page is the cursor for the next page
+		if len(self.page) != 0 {
+			cursor := self.id(self.page[len(self.page)-1])
+			if self.backward {
+				self.values.Set("ending_before", cursor)
+			} else {
+				self.values.Set("starting_after", cursor)
+			}
+		}
+	}
+
+	self.cur = self.page[0]
+	self.page = self.page[1:]
+	return true
+}
+
+// Current returns the object the iterator is positioned at.
+func (self *Iter[T]) Current() T {
+	return self.cur
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (self *Iter[T]) Err() error {
+	return self.err
+}
@@ -0,0 +1,149 @@
+package stripe
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newListTestServer returns a server that pages through the customers
+// cus_1 to cus_5, two at a time, newest first.
+func newListTestServer(requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RawQuery)
+		ids := []string{"cus_5", "cus_4", "cus_3", "cus_2", "cus_1"}
+
+		start, end := 0, len(ids)
+		for i, id := range ids {
+			if id == r.FormValue("starting_after") {
+				start = i + 1
+			}
+			if id == r.FormValue("ending_before") {
+				end = i
+			}
+		}
+		if r.FormValue("ending_before") != "" && end-start > 2 {
+			start = end - 2
+		}
+		if start+2 < end {
+			end = start + 2
+		}
+
+		data := []string{}
+		for _, id := range ids[start:end] {
+			data = append(data, fmt.Sprintf(`{"id":%q}`, id))
+		}
+		hasMore := end < len(ids)
+		if r.FormValue("ending_before") != "" {
+			hasMore = start > 0
+		}
+		fmt.Fprintf(w, `{"object":"list","has_more":%v,"data":[%s]}`, hasMore, strings.Join(data, ","))
+	}))
+}
+
+// TestIter will test that an Iter walks every page of a list, and only
+// fetches a page once it is needed.
+func TestIter(t *testing.T) {
+	requests := []string{}
+	server := newListTestServer(&requests)
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	iter := client.Customers.Iter(&ListParams{Limit: 2})
+	ids := []string{}
+	for iter.Next() {
+		ids = append(ids, iter.Current().Id)
+		if len(ids) == 1 && len(requests) != 1 {
+			t.Errorf("Expected 1 request after the first object, got %d", len(requests))
+		}
+	}
+	if err := iter.Err(); err != nil {
+		t.Errorf("Expected Customers, got Error %s", err.Error())
+	}
+	if got := strings.Join(ids, ","); got != "cus_5,cus_4,cus_3,cus_2,cus_1" {
+		t.Errorf("Expected all 5 Customers, got %s", got)
+	}
+	if len(requests) != 3 || requests[2] != "limit=2&starting_after=cus_2" {
+		t.Errorf("Expected 3 requests using the starting_after cursor, got %v", requests)
+	}
+}
+
+// TestIterBackward will test that an Iter started with an ending_before
+// cursor walks the list backwards.
+func TestIterBackward(t *testing.T) {
+	requests := []string{}
+	server := newListTestServer(&requests)
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	iter := client.Customers.Iter(&ListParams{Limit: 2, EndingBefore: "cus_1"})
+	ids := []string{}
+	for iter.Next() {
+		ids = append(ids, iter.Current().Id)
+	}
+	if got := strings.Join(ids, ","); got != "cus_2,cus_3,cus_4,cus_5" {
+		t.Errorf("Expected Customers cus_2 to cus_5, got %s", got)
+	}
+}
+
+// TestIterError will test that an error stops the iteration and is reported
+// by Err.
+func TestIterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"No such customer"}}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	iter := client.Charges.Iter(nil)
+	if iter.Next() {
+		t.Errorf("Expected no Charges")
+	}
+	if iter.Err() == nil || iter.Err().Error() != "No such customer" {
+		t.Errorf("Expected Error No such customer, got %v", iter.Err())
+	}
+}
+
+// TestPage will test that list filters are encoded in the request, and that
+// the returned page reports whether more objects are available.
+func TestPage(t *testing.T) {
+	query := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"object":"list","has_more":true,"data":[{"id":"ch_1"}]}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := ChargeListParams{
+		ListParams: ListParams{
+			Limit:   1,
+			Created: &RangeQuery{Gte: 1393632000, Lt: 1396310400},
+		},
+		Customer: "cus_1",
+	}
+	page, err := client.Charges.Page(&params)
+	if err != nil {
+		t.Errorf("Expected Charges, got Error %s", err.Error())
+		return
+	}
+	if !page.HasMore || len(page.Data) != 1 || page.Data[0].Id != "ch_1" {
+		t.Errorf("Expected a page with Charge ch_1 and more to come, got %+v", page)
+	}
+
+	want := "created%5Bgte%5D=1393632000&created%5Blt%5D=1396310400&customer=cus_1&limit=1"
+	if query != want {
+		t.Errorf("Expected query %s, got %s", want, query)
+	}
+}
@@ -0,0 +1,78 @@
+package stripe
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Logger is a leveled, structured logger used by a Client to log requests
+// and responses. Each message is followed by alternating key/value pairs.
+// It is satisfied by *slog.Logger.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// logRetry logs that a failed request is about to be retried.
+func (self *Client) logRetry(method, path string, attempt int, delay time.Duration, err error) {
+	if self.Logger == nil {
+		return
+	}
+	self.Logger.Warn("stripe: retrying request",
+		"method", method,
+		"path", redact(path),
+		"attempt", attempt,
+		"delay", delay,
+		"error", redact(err.Error()))
+}
+
+// Patterns matching sensitive data that must never be written to a log.
+var (
+	// API keys (ie sk_live_xxx) and tokens (ie tok_xxx)
+	redactKeyPattern = regexp.MustCompile(`\b(sk|rk|tok)_([0-9A-Za-z_]*_)?[0-9A-Za-z]+`)
+
+	// card numbers, which are 12 to 19 digits long
+	redactCardPattern = regexp.MustCompile(`\b\d{8,15}(\d{4})\b`)
+
+	// card security codes in a JSON document
+	redactCVCPattern = regexp.MustCompile(`"cvc"\s*:\s*"[^"]*"`)
+)
+
+// redact masks card numbers, CVCs, API keys and tokens in the given string.
+func redact(s string) string {
+	s = redactKeyPattern.ReplaceAllString(s, "${1}_${2}********")
+	s = redactCardPattern.ReplaceAllString(s, "********${1}")
+	s = redactCVCPattern.ReplaceAllString(s, `"cvc":"***"`)
+	return s
+}
+
+// redactValues returns the parameters, unescaped for readability, with card
+// numbers, CVCs, API keys and tokens masked.
+func redactValues(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	params := []string{}
+	for _, key := range keys {
+		for _, val := range values[key] {
+			switch {
+			case key == "cvc" || strings.HasSuffix(key, "[cvc]"):
+				val = "***"
+			case key == "number" || strings.HasSuffix(key, "[number]"):
+				if len(val) > 4 {
+					val = "********" + val[len(val)-4:]
+				}
+			}
+			params = append(params, key+"="+val)
+		}
+	}
+	return redact(strings.Join(params, "&"))
+}
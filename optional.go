@@ -0,0 +1,60 @@
+package stripe
+
+import (
+	"reflect"
+)
+
+// Optional is a request parameter that distinguishes between a value that
+// was never set (and is not sent), a value that was explicitly set, including
+// the zero value (ie false or 0), and a value that was explicitly set to null,
+// which clears the field of the Stripe object. The zero Optional is not set.
+//
+//	params := stripe.CustomerParams{
+//		Email:          stripe.Value("george.costanza@mail.com"),
+//		Desc:           stripe.Null[string](), // clears the description
+//		AccountBalance: stripe.Value[int64](0),
+//	}
+type Optional[T any] struct {
+	value T
+	state optionalState
+}
+
+type optionalState int
+
+const (
+	optionalUnset optionalState = iota
+	optionalSet
+	optionalNull
+)
+
+// Value returns an Optional parameter that is set to v.
+func Value[T any](v T) Optional[T] {
+	return Optional[T]{value: v, state: optionalSet}
+}
+
+// Null returns an Optional parameter that clears the field of the Stripe
+// object, by sending an empty value.
+func Null[T any]() Optional[T] {
+	return Optional[T]{state: optionalNull}
+}
+
+// Get returns the value of the parameter, and whether it was set. A null
+// parameter is not set.
+func (self Optional[T]) Get() (T, bool) {
+	return self.value, self.state == optionalSet
+}
+
+// IsNull returns true if the parameter clears the field of the Stripe object.
+func (self Optional[T]) IsNull() bool {
+	return self.state == optionalNull
+}
+
+// formValue implements the optionalParam interface used by the form encoder.
+func (self Optional[T]) formValue() (reflect.Value, optionalState) {
+	return reflect.ValueOf(self.value), self.state
+}
+
+// optionalParam is implemented by every Optional type.
+type optionalParam interface {
+	formValue() (reflect.Value, optionalState)
+}
@@ -0,0 +1,44 @@
+package stripe
+
+import (
+	"net/url"
+	"testing"
+)
+
+// TestOptional will test that an Optional parameter is omitted when not set,
+// is sent when set to a zero value, and is sent empty when set to null.
+func TestOptional(t *testing.T) {
+	params := CustomerParams{
+		Email:          Null[string](),
+		AccountBalance: Value[int64](0),
+	}
+	values := url.Values{}
+	appendParamsToValues(&params, &values)
+
+	want := "account_balance=0&email="
+	if got := values.Encode(); got != want {
+		t.Errorf("Expected values %s, got %s", want, got)
+	}
+
+	sub := SubscriptionParams{Plan: "gold", Prorate: Value(false)}
+	values = url.Values{}
+	appendParamsToValues(&sub, &values)
+
+	want = "plan=gold&prorate=false"
+	if got := values.Encode(); got != want {
+		t.Errorf("Expected values %s, got %s", want, got)
+	}
+}
+
+// TestOptionalGet will test the state reported by an Optional parameter.
+func TestOptionalGet(t *testing.T) {
+	if _, ok := (Optional[bool]{}).Get(); ok {
+		t.Errorf("Expected zero Optional not to be set")
+	}
+	if v, ok := Value(false).Get(); !ok || v {
+		t.Errorf("Expected Optional set to false, got %v %v", v, ok)
+	}
+	if _, ok := Null[string]().Get(); ok || !Null[string]().IsNull() {
+		t.Errorf("Expected null Optional not to be set")
+	}
+}
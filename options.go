@@ -0,0 +1,138 @@
+package stripe
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestOption configures a single Stripe API request. Options are accepted
+// as the trailing arguments of every API method.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the per-request settings collected from a list of
+// RequestOptions.
+type requestOptions struct {
+	idempotencyKey string
+	expand         []string
+	response       *Response
+	stripeAccount  string
+	key            string
+	version        string
+	timeout        time.Duration
+
+	// a file sent as a multipart/form-data body, see Client.upload
+	upload *upload
+}
+
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// StripeAccount makes the request on behalf of the connected account with the
+// given ID, by sending the Stripe-Account header.
+//
+// see https://stripe.com/docs/connect/authentication
+func StripeAccount(id string) RequestOption {
+	return func(o *requestOptions) {
+		o.stripeAccount = id
+	}
+}
+
+// APIKey authenticates the request with the given API key (ie the access
+// token of a connected account) instead of the Client's key.
+func APIKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.key = key
+	}
+}
+
+// APIVersion sends the request using the given Stripe API version instead of
+// the Client's version.
+func APIVersion(version string) RequestOption {
+	return func(o *requestOptions) {
+		o.version = version
+	}
+}
+
+// Timeout limits the time spent on the request, including any retries.
+func Timeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// IdempotencyKey attaches an Idempotency-Key header to a mutating request.
+// Stripe guarantees that requests sharing the same key are executed at most
+// once, so a request that timed out can be safely submitted again.
+//
+// see https://stripe.com/docs/api#idempotent_requests
+func IdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// Expand asks Stripe to return the full object for each of the given fields,
+// rather than just its ID. Nested fields are separated with dots, and fields
+// of the objects in a list are prefixed with "data", for example:
+//
+//	stripe.Charges.Retrieve(id, stripe.Expand("customer", "invoice"))
+//	stripe.Charges.Page(nil, stripe.Expand("data.customer"))
+//
+// see https://stripe.com/docs/api#expand
+func Expand(fields ...string) RequestOption {
+	return func(o *requestOptions) {
+		o.expand = append(o.expand, fields...)
+	}
+}
+
+// RecordResponse stores details about the http.Response of the request,
+// such as whether Stripe replayed a previous result, in resp.
+func RecordResponse(resp *Response) RequestOption {
+	return func(o *requestOptions) {
+		o.response = resp
+	}
+}
+
+// NewIdempotencyKey returns a random (version 4) UUID suitable for use as an
+// idempotency key.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Response holds details about the http.Response to a Stripe API request.
+type Response struct {
+	// The HTTP status code returned by Stripe.
+	StatusCode int
+
+	// The ID Stripe assigned to the request (the Request-Id header).
+	RequestId string
+
+	// The idempotency key sent with the request, if any.
+	IdempotencyKey string
+
+	// True if Stripe returned the saved result of an earlier request with
+	// the same idempotency key, rather than executing the request again.
+	Replayed bool
+
+	// The HTTP headers returned by Stripe.
+	Header http.Header
+}
+
+func (self *Response) set(r *http.Response, idempotencyKey string) {
+	self.StatusCode = r.StatusCode
+	self.RequestId = r.Header.Get("Request-Id")
+	self.IdempotencyKey = idempotencyKey
+	self.Replayed = r.Header.Get("Idempotent-Replayed") == "true"
+	self.Header = r.Header
+}
@@ -0,0 +1,123 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestIdempotencyKey will test that the Idempotency-Key header is sent with a
+// POST request, that the request is retried, and that a replayed response is
+// reported back to the caller.
+func TestIdempotencyKey(t *testing.T) {
+	keys := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.Write([]byte(`{"id":"ch_1","paid":true}`))
+	}))
+	defer server.Close()
+
+	resp := Response{}
+	client := newRetryTestClient(server)
+	_, err := client.Charges.Create(&charge1, IdempotencyKey("order-42"), RecordResponse(&resp))
+	if err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+		return
+	}
+	if len(keys) != 2 || keys[0] != "order-42" || keys[1] != "order-42" {
+		t.Errorf("Expected 2 requests with Idempotency-Key order-42, got %v", keys)
+	}
+	if !resp.Replayed {
+		t.Errorf("Expected Response Replayed true, got false")
+	}
+	if resp.IdempotencyKey != "order-42" {
+		t.Errorf("Expected Response IdempotencyKey order-42, got %s", resp.IdempotencyKey)
+	}
+}
+
+// TestAutoIdempotencyKey will test that a key is generated for POST requests
+// when enabled on the Client, but never for GET requests.
+func TestAutoIdempotencyKey(t *testing.T) {
+	keys := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys[r.Method] = r.Header.Get("Idempotency-Key")
+		w.Write([]byte(`{"id":"ch_1"}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server)
+	client.AutoIdempotencyKey = true
+	client.Charges.Refund("ch_1")
+	client.Charges.Retrieve("ch_1")
+
+	if len(keys["POST"]) != 36 {
+		t.Errorf("Expected generated Idempotency-Key, got %q", keys["POST"])
+	}
+	if keys["GET"] != "" {
+		t.Errorf("Expected no Idempotency-Key on GET, got %q", keys["GET"])
+	}
+}
+
+// TestConnectOptions will test that a request can be made on behalf of a
+// connected account, using its own API key and API version.
+func TestConnectOptions(t *testing.T) {
+	headers := http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		w.Write([]byte(`{"id":"cus_1"}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server)
+	_, err := client.Customers.Retrieve("cus_1",
+		StripeAccount("acct_1"),
+		APIKey("sk_test_connected"),
+		APIVersion("2014-08-20"),
+	)
+	if err != nil {
+		t.Errorf("Expected Customer, got Error %s", err.Error())
+		return
+	}
+
+	if got := headers.Get("Stripe-Account"); got != "acct_1" {
+		t.Errorf("Expected Stripe-Account acct_1, got %s", got)
+	}
+	if got := headers.Get("Stripe-Version"); got != "2014-08-20" {
+		t.Errorf("Expected Stripe-Version 2014-08-20, got %s", got)
+	}
+	req := http.Request{Header: headers}
+	if key, _, _ := req.BasicAuth(); key != "sk_test_connected" {
+		t.Errorf("Expected API key sk_test_connected, got %s", key)
+	}
+
+	// the Client itself is left untouched
+	client.Customers.Retrieve("cus_1")
+	if got := headers.Get("Stripe-Account"); got != "" {
+		t.Errorf("Expected no Stripe-Account, got %s", got)
+	}
+}
+
+// TestTimeoutOption will test that a per-request timeout aborts a slow
+// request.
+func TestTimeoutOption(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client := newRetryTestClient(server)
+	_, err := client.Customers.Retrieve("cus_1", Timeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Error %s, got %v", context.DeadlineExceeded, err)
+	}
+}
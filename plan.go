@@ -1,6 +1,7 @@
 package stripe
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 )
@@ -29,63 +30,94 @@ type Plan struct {
 
 // PlanClient encapsulates operations for creating, updating, deleting and
 // querying plans using the Stripe REST API.
-type PlanClient struct{}
+type PlanClient struct {
+	client *Client
+}
 
 // PlanParams encapsulates options for creating a new Plan.
 type PlanParams struct {
 	// Unique string of your choice that will be used to identify this plan
 	// when subscribing a customer.
-	Id string
+	Id string `form:"id"`
 
 	// A positive integer in cents (or 0 for a free plan) representing how much
 	// to charge (on a recurring basis)
-	Amount int64
+	Amount int64 `form:"amount"`
 
 	// 3-letter ISO code for currency. Currently, only 'usd' is supported.
-	Currency string
+	Currency string `form:"currency"`
 
 	// Specifies billing frequency. Either month or year.
-	Interval string
+	Interval string `form:"interval"`
 
 	// Name of the plan, to be displayed on invoices and in the web interface.
-	Name string
+	Name string `form:"name"`
 
 	// (Optional) Specifies a trial period in (an integer number of) days. If
 	// you include a trial period, the customer won't be billed for the first
 	// time until the trial period ends. If the customer cancels before the
 	// trial period is over, she'll never be billed at all.
-	TrialPeriodDays int
+	TrialPeriodDays int `form:"trial_period_days,omitempty"`
+}
+
+// Validate checks the params against the rules enforced by Stripe, without
+// making a request, and returns a ValidationError listing every invalid
+// parameter.
+func (self *PlanParams) Validate() error {
+	errs := fieldErrors{}
+	if self.Id == "" {
+		errs.add("id", "is required")
+	}
+	if self.Amount < 0 {
+		errs.add("amount", "must not be negative")
+	}
+	if !isCurrency(self.Currency) {
+		errs.add("currency", "must be a 3-letter ISO code")
+	}
+	if self.Interval != IntervalMonth && self.Interval != IntervalYear {
+		errs.add("interval", "must be month or year")
+	}
+	if self.Name == "" {
+		errs.add("name", "is required")
+	}
+	if self.TrialPeriodDays < 0 {
+		errs.add("trial_period_days", "must not be negative")
+	}
+	return errs.err()
 }
 
 // Creates a new Plan.
 //
 // see https://stripe.com/docs/api#create_plan
-func (self *PlanClient) Create(params *PlanParams) (*Plan, error) {
-	plan := Plan{}
-	values := url.Values{
-		"id":       {params.Id},
-		"name":     {params.Name},
-		"amount":   {strconv.FormatInt(params.Amount, 10)},
-		"interval": {params.Interval},
-		"currency": {params.Currency},
-	}
+func (self *PlanClient) Create(params *PlanParams, opts ...RequestOption) (*Plan, error) {
+	return self.CreateContext(context.Background(), params, opts...)
+}
 
-	// trial_period_days is optional, add if specified
-	if params.TrialPeriodDays != 0 {
-		values.Add("trial_period_days", strconv.Itoa(params.TrialPeriodDays))
+// CreateContext is the context-aware version of Create.
+func (self *PlanClient) CreateContext(ctx context.Context, params *PlanParams, opts ...RequestOption) (*Plan, error) {
+	plan := Plan{}
+	if err := self.client.validate(params); err != nil {
+		return &plan, err
 	}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
 
-	err := query("POST", "/v1/plans", values, &plan)
+	err := self.client.query(ctx, "POST", "/v1/plans", values, &plan, opts...)
 	return &plan, err
 }
 
 // Retrieves the plan with the given ID.
 //
 // see https://stripe.com/docs/api#retrieve_plan
-func (self *PlanClient) Retrieve(id string) (*Plan, error) {
+func (self *PlanClient) Retrieve(id string, opts ...RequestOption) (*Plan, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *PlanClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*Plan, error) {
 	plan := Plan{}
 	path := "/v1/plans/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &plan)
+	err := self.client.query(ctx, "GET", path, nil, &plan, opts...)
 	return &plan, err
 }
 
@@ -93,21 +125,31 @@ func (self *PlanClient) Retrieve(id string) (*Plan, error) {
 // by design, not editable.
 //
 // see https://stripe.com/docs/api#update_plan
-func (self *PlanClient) Update(id string, newName string) (*Plan, error) {
+func (self *PlanClient) Update(id string, newName string, opts ...RequestOption) (*Plan, error) {
+	return self.UpdateContext(context.Background(), id, newName, opts...)
+}
+
+// UpdateContext is the context-aware version of Update.
+func (self *PlanClient) UpdateContext(ctx context.Context, id string, newName string, opts ...RequestOption) (*Plan, error) {
 	values := url.Values{"name": {newName}}
 	plan := Plan{}
 	path := "/v1/plans/" + url.QueryEscape(id)
-	err := query("POST", path, values, &plan)
+	err := self.client.query(ctx, "POST", path, values, &plan, opts...)
 	return &plan, err
 }
 
 // Deletes a plan with the given ID.
 //
 // see https://stripe.com/docs/api#delete_plan
-func (self *PlanClient) Delete(id string) (bool, error) {
+func (self *PlanClient) Delete(id string, opts ...RequestOption) (bool, error) {
+	return self.DeleteContext(context.Background(), id, opts...)
+}
+
+// DeleteContext is the context-aware version of Delete.
+func (self *PlanClient) DeleteContext(ctx context.Context, id string, opts ...RequestOption) (bool, error) {
 	resp := DeleteResp{}
 	path := "/v1/plans/" + url.QueryEscape(id)
-	if err := query("DELETE", path, nil, &resp); err != nil {
+	if err := self.client.query(ctx, "DELETE", path, nil, &resp, opts...); err != nil {
 		return false, err
 	}
 	return resp.Deleted, nil
@@ -116,14 +158,24 @@ func (self *PlanClient) Delete(id string) (bool, error) {
 // Returns a list of your Plans.
 //
 // see https://stripe.com/docs/api#list_Plans
-func (self *PlanClient) List() ([]*Plan, error) {
-	return self.ListN(10, 0)
+func (self *PlanClient) List(opts ...RequestOption) ([]*Plan, error) {
+	return self.ListContext(context.Background(), opts...)
+}
+
+// ListContext is the context-aware version of List.
+func (self *PlanClient) ListContext(ctx context.Context, opts ...RequestOption) ([]*Plan, error) {
+	return self.ListNContext(ctx, 10, 0, opts...)
 }
 
 // Returns a list of your Plans at the specified range.
 //
 // see https://stripe.com/docs/api#list_Plans
-func (self *PlanClient) ListN(count int, offset int) ([]*Plan, error) {
+func (self *PlanClient) ListN(count int, offset int, opts ...RequestOption) ([]*Plan, error) {
+	return self.ListNContext(context.Background(), count, offset, opts...)
+}
+
+// ListNContext is the context-aware version of ListN.
+func (self *PlanClient) ListNContext(ctx context.Context, count int, offset int, opts ...RequestOption) ([]*Plan, error) {
 	// define a wrapper function for the Plan List, so that we can
 	// cleanly parse the JSON
 	type listPlanResp struct{ Data []*Plan }
@@ -135,9 +187,47 @@ func (self *PlanClient) ListN(count int, offset int) ([]*Plan, error) {
 		"offset": {strconv.Itoa(offset)},
 	}
 
-	err := query("GET", "/v1/plans", values, &resp)
+	err := self.client.query(ctx, "GET", "/v1/plans", values, &resp, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return resp.Data, nil
 }
+
+// Page returns a single page of your Plans, filtered by the given params.
+// The returned List reports whether more Plans are available.
+//
+// see https://stripe.com/docs/api#list_Plans
+func (self *PlanClient) Page(params *ListParams, opts ...RequestOption) (*List[*Plan], error) {
+	return self.PageContext(context.Background(), params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *PlanClient) PageContext(ctx context.Context, params *ListParams, opts ...RequestOption) (*List[*Plan], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, values, opts...)
+}
+
+// Iter returns an iterator over all of your Plans, filtered by the given params,
+// which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_Plans
+func (self *PlanClient) Iter(params *ListParams, opts ...RequestOption) *Iter[*Plan] {
+	return self.IterContext(context.Background(), params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *PlanClient) IterContext(ctx context.Context, params *ListParams, opts ...RequestOption) *Iter[*Plan] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Plan], error) {
+		return self.page(ctx, values, opts...)
+	}, func(p *Plan) string { return p.Id })
+}
+
+func (self *PlanClient) page(ctx context.Context, values url.Values, opts ...RequestOption) (*List[*Plan], error) {
+	list := List[*Plan]{}
+	err := self.client.query(ctx, "GET", "/v1/plans", values, &list, opts...)
+	return &list, err
+}
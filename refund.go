@@ -0,0 +1,148 @@
+package stripe
+
+import (
+	"context"
+	"net/url"
+)
+
+// Refund Reasons
+const (
+	RefundReasonDuplicate           = "duplicate"
+	RefundReasonFraudulent          = "fraudulent"
+	RefundReasonRequestedByCustomer = "requested_by_customer"
+)
+
+// Refund represents a full or partial refund of a Charge. A Charge may be
+// refunded in several parts, each with its own Refund.
+//
+// see https://stripe.com/docs/api#refund_object
+type Refund struct {
+	Id                 string                         `json:"id"`
+	Amount             int64                          `json:"amount"`
+	Currency           string                         `json:"currency"`
+	Created            int64                          `json:"created"`
+	Charge             Expandable[Charge]             `json:"charge"`
+	Reason             String                         `json:"reason"`
+	BalanceTransaction Expandable[BalanceTransaction] `json:"balance_transaction"`
+	Metadata           map[string]string              `json:"metadata"`
+}
+
+// RefundParams encapsulates options for creating a Refund.
+type RefundParams struct {
+	// (Optional) A positive integer in cents representing how much of the
+	// charge to refund. Defaults to the entire remaining amount.
+	Amount int64 `form:"amount,omitempty"`
+
+	// (Optional) The reason for the refund, one of the Refund Reasons.
+	Reason string `form:"reason,omitempty"`
+
+	// (Optional) A set of key/value pairs that you can attach to a refund
+	// object.
+	Metadata map[string]string `form:"metadata,omitempty"`
+}
+
+// RefundUpdateParams encapsulates options for updating a Refund.
+type RefundUpdateParams struct {
+	// A set of key/value pairs that you can attach to a refund object. Keys
+	// that are not given are left unchanged, and a key set to an empty string
+	// is removed.
+	Metadata map[string]string `form:"metadata,omitempty"`
+}
+
+// RefundClient encapsulates operations for creating, updating and querying
+// refunds using the Stripe REST API.
+type RefundClient struct {
+	client *Client
+}
+
+// Creates a new Refund for the Charge with the given ID.
+//
+// see https://stripe.com/docs/api#create_refund
+func (self *RefundClient) Create(params *RefundParams, chargeId string, opts ...RequestOption) (*Refund, error) {
+	return self.CreateContext(context.Background(), params, chargeId, opts...)
+}
+
+// CreateContext is the context-aware version of Create.
+func (self *RefundClient) CreateContext(ctx context.Context, params *RefundParams, chargeId string, opts ...RequestOption) (*Refund, error) {
+	refund := Refund{}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+
+	path := "/v1/charges/" + url.QueryEscape(chargeId) + "/refunds"
+	err := self.client.query(ctx, "POST", path, values, &refund, opts...)
+	return &refund, err
+}
+
+// Retrieves the Refund with the given ID, belonging to the Charge with the
+// given ID.
+//
+// see https://stripe.com/docs/api#retrieve_refund
+func (self *RefundClient) Retrieve(refundId string, chargeId string, opts ...RequestOption) (*Refund, error) {
+	return self.RetrieveContext(context.Background(), refundId, chargeId, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *RefundClient) RetrieveContext(ctx context.Context, refundId string, chargeId string, opts ...RequestOption) (*Refund, error) {
+	refund := Refund{}
+	path := "/v1/charges/" + url.QueryEscape(chargeId) + "/refunds/" + url.QueryEscape(refundId)
+	err := self.client.query(ctx, "GET", path, nil, &refund, opts...)
+	return &refund, err
+}
+
+// Updates the metadata of the Refund with the given ID, belonging to the
+// Charge with the given ID.
+//
+// see https://stripe.com/docs/api#update_refund
+func (self *RefundClient) Update(refundId string, chargeId string, params *RefundUpdateParams, opts ...RequestOption) (*Refund, error) {
+	return self.UpdateContext(context.Background(), refundId, chargeId, params, opts...)
+}
+
+// UpdateContext is the context-aware version of Update.
+func (self *RefundClient) UpdateContext(ctx context.Context, refundId string, chargeId string, params *RefundUpdateParams, opts ...RequestOption) (*Refund, error) {
+	refund := Refund{}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+
+	path := "/v1/charges/" + url.QueryEscape(chargeId) + "/refunds/" + url.QueryEscape(refundId)
+	err := self.client.query(ctx, "POST", path, values, &refund, opts...)
+	return &refund, err
+}
+
+// Page returns a single page of the Refunds of the Charge with the given ID.
+// The returned List reports whether more Refunds are available.
+//
+// see https://stripe.com/docs/api#list_refunds
+func (self *RefundClient) Page(chargeId string, params *ListParams, opts ...RequestOption) (*List[*Refund], error) {
+	return self.PageContext(context.Background(), chargeId, params, opts...)
+}
+
+// PageContext is the context-aware version of Page.
+func (self *RefundClient) PageContext(ctx context.Context, chargeId string, params *ListParams, opts ...RequestOption) (*List[*Refund], error) {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return self.page(ctx, chargeId, values, opts...)
+}
+
+// Iter returns an iterator over all of the Refunds of the Charge with the
+// given ID, which fetches them from Stripe one page at a time.
+//
+// see https://stripe.com/docs/api#list_refunds
+func (self *RefundClient) Iter(chargeId string, params *ListParams, opts ...RequestOption) *Iter[*Refund] {
+	return self.IterContext(context.Background(), chargeId, params, opts...)
+}
+
+// IterContext is the context-aware version of Iter.
+func (self *RefundClient) IterContext(ctx context.Context, chargeId string, params *ListParams, opts ...RequestOption) *Iter[*Refund] {
+	values := url.Values{}
+	appendParamsToValues(params, &values)
+	return newIter(values, func(values url.Values) (*List[*Refund], error) {
+		return self.page(ctx, chargeId, values, opts...)
+	}, func(r *Refund) string { return r.Id })
+}
+
+func (self *RefundClient) page(ctx context.Context, chargeId string, values url.Values, opts ...RequestOption) (*List[*Refund], error) {
+	list := List[*Refund]{}
+	path := "/v1/charges/" + url.QueryEscape(chargeId) + "/refunds"
+	err := self.client.query(ctx, "GET", path, values, &list, opts...)
+	return &list, err
+}
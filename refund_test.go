@@ -0,0 +1,178 @@
+package stripe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCreateRefund will test that a partial refund is sent with its reason
+// and metadata, and that the Refund is decoded.
+func TestCreateRefund(t *testing.T) {
+	path, body := "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		path, body = r.URL.Path, r.PostForm.Encode()
+		w.Write([]byte(`{"id":"re_1","amount":100,"charge":"ch_1","reason":"duplicate","metadata":{"ticket":"42"}}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := RefundParams{
+		Amount:   100,
+		Reason:   RefundReasonDuplicate,
+		Metadata: map[string]string{"ticket": "42"},
+	}
+	refund, err := client.Refunds.Create(&params, "ch_1")
+	if err != nil {
+		t.Errorf("Expected Refund, got Error %s", err.Error())
+		return
+	}
+	if refund.Id != "re_1" || refund.Amount != 100 || refund.Charge.Id != "ch_1" {
+		t.Errorf("Expected Refund re_1 of 100 for Charge ch_1, got %+v", refund)
+	}
+	if refund.Reason != RefundReasonDuplicate || refund.Metadata["ticket"] != "42" {
+		t.Errorf("Expected Refund reason and metadata, got %+v", refund)
+	}
+
+	if path != "/v1/charges/ch_1/refunds" {
+		t.Errorf("Expected path /v1/charges/ch_1/refunds, got %s", path)
+	}
+	want := "amount=100&metadata%5Bticket%5D=42&reason=duplicate"
+	if body != want {
+		t.Errorf("Expected body %s, got %s", want, body)
+	}
+}
+
+// TestChargeRefunds will test that the refunds of a Charge are decoded both
+// as a list and as the plain array returned by older API versions.
+func TestChargeRefunds(t *testing.T) {
+	tests := []string{
+		`{"id":"ch_1","refunds":{"object":"list","has_more":false,"data":[{"id":"re_1","amount":100},{"id":"re_2","amount":50}]}}`,
+		`{"id":"ch_1","refunds":[{"id":"re_1","amount":100},{"id":"re_2","amount":50}]}`,
+	}
+	for _, test := range tests {
+		charge := Charge{}
+		if err := json.Unmarshal([]byte(test), &charge); err != nil {
+			t.Errorf("Expected Charge, got Error %s", err.Error())
+			continue
+		}
+		refunds := charge.Refunds.Data
+		if len(refunds) != 2 || refunds[0].Amount != 100 || refunds[1].Amount != 50 {
+			t.Errorf("Expected 2 Refunds of 100 and 50, got %v", refunds)
+		}
+	}
+}
+
+// TestRetrieveRefund will test that a Refund is retrieved from its Charge, and
+// that the expanded balance transaction is decoded.
+func TestRetrieveRefund(t *testing.T) {
+	method, path := "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.Write([]byte(`{"id":"re_1","amount":100,"charge":"ch_1","balance_transaction":{"id":"txn_1","amount":-100}}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	refund, err := client.Refunds.Retrieve("re_1", "ch_1")
+	if err != nil {
+		t.Errorf("Expected Refund, got Error %s", err.Error())
+		return
+	}
+	if refund.Id != "re_1" || refund.Amount != 100 {
+		t.Errorf("Expected Refund re_1 of 100, got %+v", refund)
+	}
+	if !refund.BalanceTransaction.Expanded() || refund.BalanceTransaction.Object.Amount != -100 {
+		t.Errorf("Expected expanded Balance Transaction txn_1, got %+v", refund.BalanceTransaction)
+	}
+	if method != "GET" || path != "/v1/charges/ch_1/refunds/re_1" {
+		t.Errorf("Expected GET /v1/charges/ch_1/refunds/re_1, got %s %s", method, path)
+	}
+}
+
+// TestUpdateRefund will test that only the metadata of a Refund is sent when
+// it is updated.
+func TestUpdateRefund(t *testing.T) {
+	method, path, body := "", "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		method, path, body = r.Method, r.URL.Path, r.PostForm.Encode()
+		w.Write([]byte(`{"id":"re_1","amount":100,"metadata":{"ticket":"43"}}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	params := RefundUpdateParams{Metadata: map[string]string{"ticket": "43"}}
+	refund, err := client.Refunds.Update("re_1", "ch_1", &params)
+	if err != nil {
+		t.Errorf("Expected Refund, got Error %s", err.Error())
+		return
+	}
+	if refund.Metadata["ticket"] != "43" {
+		t.Errorf("Expected Refund metadata ticket 43, got %+v", refund.Metadata)
+	}
+	if method != "POST" || path != "/v1/charges/ch_1/refunds/re_1" {
+		t.Errorf("Expected POST /v1/charges/ch_1/refunds/re_1, got %s %s", method, path)
+	}
+	if want := "metadata%5Bticket%5D=43"; body != want {
+		t.Errorf("Expected body %s, got %s", want, body)
+	}
+}
+
+// TestListRefunds will test that the refunds of a Charge are listed one page
+// at a time, and that an Iter follows the pages to the end.
+func TestListRefunds(t *testing.T) {
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		if r.FormValue("starting_after") == "" {
+			w.Write([]byte(`{"object":"list","has_more":true,"data":[{"id":"re_2","amount":50}]}`))
+			return
+		}
+		w.Write([]byte(`{"object":"list","has_more":false,"data":[{"id":"re_1","amount":100}]}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	page, err := client.Refunds.Page("ch_1", &ListParams{Limit: 1})
+	if err != nil {
+		t.Errorf("Expected Refunds, got Error %s", err.Error())
+		return
+	}
+	if len(page.Data) != 1 || page.Data[0].Id != "re_2" || !page.HasMore {
+		t.Errorf("Expected first page with Refund re_2, got %+v", page)
+	}
+
+	ids := []string{}
+	iter := client.Refunds.Iter("ch_1", &ListParams{Limit: 1})
+	for iter.Next() {
+		ids = append(ids, iter.Current().Id)
+	}
+	if err := iter.Err(); err != nil {
+		t.Errorf("Expected Refunds, got Error %s", err.Error())
+	}
+	if len(ids) != 2 || ids[0] != "re_2" || ids[1] != "re_1" {
+		t.Errorf("Expected Refunds re_2 and re_1, got %v", ids)
+	}
+
+	want := []string{
+		"/v1/charges/ch_1/refunds?limit=1",
+		"/v1/charges/ch_1/refunds?limit=1",
+		"/v1/charges/ch_1/refunds?limit=1&starting_after=re_2",
+	}
+	for i := range want {
+		if i >= len(queries) || queries[i] != want[i] {
+			t.Errorf("Expected request %s, got %v", want[i], queries)
+		}
+	}
+}
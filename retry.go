@@ -0,0 +1,88 @@
+package stripe
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// shouldRetry reports whether a failed request may be safely submitted again.
+// The http.Response is nil if the request failed with a network error.
+func (self *Client) shouldRetry(req *http.Request, r *http.Response, attempt int) bool {
+	if attempt > self.MaxRetries || req.Context().Err() != nil {
+		return false
+	}
+
+	// a POST without an idempotency key is never retried. If the first
+	// attempt reached Stripe we could, for example, charge the card twice.
+	if req.Method == "POST" && req.Header.Get("Idempotency-Key") == "" {
+		return false
+	}
+
+	// network errors (ie connection reset) are always safe to retry
+	if r == nil {
+		return true
+	}
+
+	switch r.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the given retry attempt, using
+// capped exponential backoff with jitter. A Retry-After header on a 429
+// response is honored if it asks us to wait longer.
+func (self *Client) retryDelay(attempt int, r *http.Response) time.Duration {
+	delay := self.MinRetryDelay << uint(attempt-1)
+	if delay > self.MaxRetryDelay || delay <= 0 {
+		delay = self.MaxRetryDelay
+	}
+
+	// randomize the delay between 50% and 100% of its value
+	if delay > 1 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+
+	if r != nil && r.StatusCode == http.StatusTooManyRequests {
+		if after := parseRetryAfter(r.Header.Get("Retry-After")); after > delay {
+			delay = after
+		}
+	}
+	return delay
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// sleep pauses for the given duration, returning early with an error if the
+// context is cancelled first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
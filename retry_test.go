@@ -0,0 +1,112 @@
+package stripe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newRetryTestClient returns a Client for the given test server that retries
+// without any noticeable delay.
+func newRetryTestClient(server *httptest.Server) *Client {
+	client := New("sk_test_1")
+	client.Url = server.URL
+	client.MaxRetries = 2
+	client.MinRetryDelay = time.Millisecond
+	client.MaxRetryDelay = time.Millisecond
+	return client
+}
+
+// TestRetryGet will test that a GET request is retried after a 503 response,
+// and succeeds once Stripe recovers.
+func TestRetryGet(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"ch_1"}`))
+	}))
+	defer server.Close()
+
+	charge, err := newRetryTestClient(server).Charges.Retrieve("ch_1")
+	if err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+		return
+	}
+	if charge.Id != "ch_1" {
+		t.Errorf("Expected Charge Id ch_1, got %s", charge.Id)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+// TestRetryExhausted will test that the returned error reports the number of
+// attempts made once all retries are used up.
+func TestRetryExhausted(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"Too many requests"}}`))
+	}))
+	defer server.Close()
+
+	_, err := newRetryTestClient(server).Charges.Retrieve("ch_1")
+	rateErr, ok := err.(*RateLimitError)
+	if !ok {
+		t.Errorf("Expected RateLimitError, got %v", err)
+		return
+	}
+	stripeErr := rateErr.Err
+	if stripeErr.Attempts != 3 || requests != 3 {
+		t.Errorf("Expected 3 attempts, got %d (%d requests)", stripeErr.Attempts, requests)
+	}
+}
+
+// TestRetryPost will test that a POST without an idempotency key is never
+// retried, since it could charge a card twice.
+func TestRetryPost(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	newRetryTestClient(server).Charges.Refund("ch_1")
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+// TestRetryConnection will test that network errors are retried and reported
+// as a ConnectionError.
+func TestRetryConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	client := newRetryTestClient(server)
+	server.Close()
+
+	_, err := client.Charges.Retrieve("ch_1")
+	connErr, ok := err.(*ConnectionError)
+	if !ok {
+		t.Errorf("Expected ConnectionError, got %v", err)
+		return
+	}
+	if connErr.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", connErr.Attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("2"); d != 2*time.Second {
+		t.Errorf("Expected Retry-After 2s, got %s", d)
+	}
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Errorf("Expected Retry-After 0s, got %s", d)
+	}
+}
@@ -1,6 +1,7 @@
 package stripe
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,65 +11,269 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 )
 
-// enable logging to print the request and reponses to stdout
-var _log bool
-
-// the API Key used to authenticate all Stripe API requests
-var _key string
-
 // the default URL for all Stripe API requests
-var _url string = "https://api.stripe.com"
+const defaultUrl = "https://api.stripe.com"
 
+// the default URL for Stripe file uploads
+const defaultUploadUrl = "https://uploads.stripe.com"
+
+// the default Stripe API version sent with every request
 const apiVersion = "2013-08-13"
 
+// the Stripe API version that renamed the statement_description parameter
+// to statement_descriptor
+const versionStatementDescriptor = "2014-12-17"
+
+// the Stripe API version that replaced the free form text of dispute evidence
+// with a hash of evidence fields
+const versionDisputeEvidence = "2014-12-08"
+
+// the Stripe API version that renamed the cards of a customer to sources,
+// and its default_card parameter to default_source
+const versionSources = "2015-02-18"
+
+// the http.Client used when a Client does not specify its own. Unlike the
+// http.DefaultClient, it will not wait forever on a slow response.
+var defaultHTTPClient = &http.Client{Timeout: 80 * time.Second}
+
+// Client is a Stripe API client. Each Client carries its own API key, base
+// URL, API version and http.Client, so a single process can safely talk to
+// many Stripe accounts at once.
+type Client struct {
+	// The API Key used to authenticate all Stripe API requests.
+	Key string
+
+	// The base URL for all Stripe API requests.
+	Url string
+
+	// The base URL for Stripe file uploads.
+	UploadUrl string
+
+	// The Stripe API version sent with every request.
+	Version string
+
+	// The http.Client used to submit requests. If nil, a default
+	// http.Client with an 80 second timeout is used.
+	HTTPClient *http.Client
+
+	// The Logger used to log requests and responses. If nil, nothing is
+	// logged. Card numbers, CVCs, API keys and tokens are always masked.
+	Logger Logger
+
+	// The maximum number of times a request is retried after a network
+	// error, a 500, 502 or 503 response, or a 429 (rate limited) response.
+	// POST requests are only retried when they carry an idempotency key.
+	MaxRetries int
+
+	// The delay before the first retry. The delay doubles with every
+	// subsequent retry, up to MaxRetryDelay, and is randomized (jittered)
+	// so that many clients don't retry in lockstep.
+	MinRetryDelay time.Duration
+
+	// The maximum delay between two retries.
+	MaxRetryDelay time.Duration
+
+	// Automatically generate an idempotency key for every POST request that
+	// was not given one with the IdempotencyKey option. This makes all POST
+	// requests safe to retry.
+	AutoIdempotencyKey bool
+
+	// Validate Charge, Card, Token, Plan and Coupon params before they are
+	// sent, returning a ValidationError instead of making a request that
+	// Stripe would reject.
+	ValidateParams bool
+
+	// Available APIs
+	Balances      *BalanceClient
+	Cards         *CardClient
+	Charges       *ChargeClient
+	Coupons       *CouponClient
+	Customers     *CustomerClient
+	Disputes      *DisputeClient
+	Files         *FileClient
+	Invoices      *InvoiceClient
+	InvoiceItems  *InvoiceItemClient
+	Plans         *PlanClient
+	Refunds       *RefundClient
+	Subscriptions *SubscriptionClient
+	Tokens        *TokenClient
+}
+
+// New returns a Client that authenticates with the given API key, using the
+// default Stripe API URL and version.
+func New(key string) *Client {
+	c := &Client{
+		Key:       key,
+		Url:       defaultUrl,
+		UploadUrl: defaultUploadUrl,
+		Version:   apiVersion,
+
+		MaxRetries:    2,
+		MinRetryDelay: 500 * time.Millisecond,
+		MaxRetryDelay: 8 * time.Second,
+	}
+	c.Balances = &BalanceClient{c}
+	c.Cards = &CardClient{c}
+	c.Charges = &ChargeClient{c}
+	c.Coupons = &CouponClient{c}
+	c.Customers = &CustomerClient{c}
+	c.Disputes = &DisputeClient{c}
+	c.Files = &FileClient{c}
+	c.Invoices = &InvoiceClient{c}
+	c.InvoiceItems = &InvoiceItemClient{c}
+	c.Plans = &PlanClient{c}
+	c.Refunds = &RefundClient{c}
+	c.Subscriptions = &SubscriptionClient{c}
+	c.Tokens = &TokenClient{c}
+	return c
+}
+
+// the Client used by the package-level APIs below.
+var defaultClient = New("")
+
 // SetUrl will override the default Stripe API URL. This is primarily used
 // for unit testing.
 func SetUrl(url string) {
-	_url = url
+	defaultClient.Url = url
+}
+
+// SetVersion will override the default Stripe API version sent with every
+// request.
+func SetVersion(version string) {
+	defaultClient.Version = version
 }
 
 // SetKey will set the default Stripe API key used to authenticate all Stripe
 // API requests.
 func SetKey(key string) {
-	_key = key
+	defaultClient.Key = key
 }
 
-// Available APIs
+// Available APIs, using the default Client.
 var (
-	Charges       = new(ChargeClient)
-	Coupons       = new(CouponClient)
-	Customers     = new(CustomerClient)
-	Invoices      = new(InvoiceClient)
-	InvoiceItems  = new(InvoiceItemClient)
-	Plans         = new(PlanClient)
-	Subscriptions = new(SubscriptionClient)
-	Tokens        = new(TokenClient)
+	Balances      = defaultClient.Balances
+	Cards         = defaultClient.Cards
+	Charges       = defaultClient.Charges
+	Coupons       = defaultClient.Coupons
+	Customers     = defaultClient.Customers
+	Disputes      = defaultClient.Disputes
+	Files         = defaultClient.Files
+	Invoices      = defaultClient.Invoices
+	InvoiceItems  = defaultClient.InvoiceItems
+	Plans         = defaultClient.Plans
+	Refunds       = defaultClient.Refunds
+	Subscriptions = defaultClient.Subscriptions
+	Tokens        = defaultClient.Tokens
 )
 
 // SetKeyEnv retrieves the Stripe API key using the STRIPE_API_KEY environment
 // variable.
 func SetKeyEnv() (err error) {
-	_key = os.Getenv("STRIPE_API_KEY")
-	if _key == "" {
+	defaultClient.Key = os.Getenv("STRIPE_API_KEY")
+	if defaultClient.Key == "" {
 		err = errors.New("STRIPE_API_KEY not found in environment")
 	}
 	return
 }
 
+// Call submits a request to the given Stripe REST API endpoint using the
+// default Client, and decodes the JSON response into the value pointed to by
+// v. See Client.Call.
+func Call(method, path string, values url.Values, v interface{}, opts ...RequestOption) error {
+	return defaultClient.CallContext(context.Background(), method, path, values, v, opts...)
+}
+
+// CallContext is the context-aware version of Call.
+func CallContext(ctx context.Context, method, path string, values url.Values, v interface{}, opts ...RequestOption) error {
+	return defaultClient.CallContext(ctx, method, path, values, v, opts...)
+}
+
+// Call submits a request to any Stripe REST API endpoint, including those not
+// (yet) modeled by this package, and decodes the JSON response into the value
+// pointed to by v. If v is nil the response is discarded. The request goes
+// through the same authentication, versioning, logging, retries and error
+// decoding as the built-in APIs. For example:
+//
+//	transfers := map[string]interface{}{}
+//	err := client.Call("GET", "/v1/transfers", nil, &transfers)
+func (self *Client) Call(method, path string, values url.Values, v interface{}, opts ...RequestOption) error {
+	return self.CallContext(context.Background(), method, path, values, v, opts...)
+}
+
+// CallContext is the context-aware version of Call.
+func (self *Client) CallContext(ctx context.Context, method, path string, values url.Values, v interface{}, opts ...RequestOption) error {
+	return self.query(ctx, method, path, values, v, opts...)
+}
+
+// version returns the Stripe API version a request made with the given
+// options will use.
+func (self *Client) version(opts []RequestOption) string {
+	if self == nil {
+		self = defaultClient
+	}
+	if o := newRequestOptions(opts); o.version != "" {
+		return o.version
+	}
+	return self.Version
+}
+
 // query submits an http.Request and parses the JSON-encoded http.Response,
-// storing the result in the value pointed to by v.
-func query(method, path string, values url.Values, v interface{}) error {
-	// parse the stripe URL
-	endpoint, err := url.Parse(_url)
+// storing the result in the value pointed to by v. The request is bound to
+// ctx, so cancellation and deadlines are applied to the outgoing request.
+func (self *Client) query(ctx context.Context, method, path string, values url.Values, v interface{}, opts ...RequestOption) error {
+	// a zero-value API client (ie new(ChargeClient)) has no Client, in
+	// which case we fall back to the default Client.
+	if self == nil {
+		self = defaultClient
+	}
+
+	o := newRequestOptions(opts)
+	if o.idempotencyKey == "" && method == "POST" && self.AutoIdempotencyKey {
+		o.idempotencyKey = NewIdempotencyKey()
+	}
+	if o.key == "" {
+		o.key = self.Key
+	}
+	if o.version == "" {
+		o.version = self.Version
+	}
+	if o.timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	// add the expanded fields, copying the url.Values so that the caller's
+	// values (ie the cursor of an Iter) are left untouched.
+	if len(o.expand) != 0 {
+		expanded := url.Values{}
+		for key, vals := range values {
+			expanded[key] = append([]string(nil), vals...)
+		}
+		for _, field := range o.expand {
+			expanded.Add("expand[]", field)
+		}
+		values = expanded
+	}
+
+	// parse the stripe URL. Files are uploaded to their own URL.
+	base := self.Url
+	if o.upload != nil {
+		base = self.UploadUrl
+		if base == "" {
+			base = defaultUploadUrl
+		}
+	}
+	endpoint, err := url.Parse(base)
 	if err != nil {
 		return err
 	}
 
 	// set the endpoint for the specific API
 	endpoint.Path = path
-	endpoint.User = url.User(_key)
 
 	// if this is an http GET, add the url.Values to the endpoint
 	if method == "GET" {
@@ -76,53 +281,111 @@ func query(method, path string, values url.Values, v interface{}) error {
 	}
 
 	// else if this is not a GET, encode the url.Values in the body.
-	var reqBody io.Reader
+	var reqBody string
 	if method != "GET" && values != nil {
-		reqBody = strings.NewReader(values.Encode())
+		reqBody = values.Encode()
 	}
 
-	// Log request if logging enabled
-	if _log {
-		fmt.Println("REQUEST: ", method, endpoint.String())
-		fmt.Println(values.Encode())
+	// Log request if logging enabled. Card data, API keys and tokens are
+	// masked before anything is written to the log.
+	if self.Logger != nil {
+		self.Logger.Debug("stripe: request",
+			"method", method,
+			"url", redact(endpoint.Redacted()),
+			"params", redactValues(values))
 	}
 
-	// create the request
-	req, err := http.NewRequest(method, endpoint.String(), reqBody)
-	if err != nil {
-		return err
+	httpClient := self.HTTPClient
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
 	}
 
-	req.Header.Set("Stripe-Version", apiVersion)
+	for attempt := 1; ; attempt++ {
+		// create the request. The body is re-created for every attempt,
+		// since a previous attempt will have consumed it. An uploaded file
+		// is streamed as it is read, and is never retried.
+		var reqReader io.Reader = strings.NewReader(reqBody)
+		contentType := "application/x-www-form-urlencoded"
+		if o.upload != nil {
+			reqReader, contentType = o.upload.stream(values)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), reqReader)
+		if err != nil {
+			if closer, ok := reqReader.(io.Closer); ok {
+				closer.Close()
+			}
+			return err
+		}
 
-	// submit the http request
-	r, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
+		req.SetBasicAuth(o.key, "")
+		req.Header.Set("Stripe-Version", o.version)
+		if o.stripeAccount != "" {
+			req.Header.Set("Stripe-Account", o.stripeAccount)
+		}
+		if method != "GET" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if o.idempotencyKey != "" && method != "GET" {
+			req.Header.Set("Idempotency-Key", o.idempotencyKey)
+		}
 
-	// read the body of the http message into a byte array
-	body, err := ioutil.ReadAll(r.Body)
-	defer r.Body.Close()
-	if err != nil {
-		return err
-	}
+		// submit the http request and read the body of the http message
+		// into a byte array
+		r, err := httpClient.Do(req)
+		var body []byte
+		if err == nil {
+			body, err = ioutil.ReadAll(r.Body)
+			r.Body.Close()
+		}
+		if err != nil {
+			if o.upload == nil && self.shouldRetry(req, nil, attempt) {
+				delay := self.retryDelay(attempt, nil)
+				self.logRetry(method, path, attempt, delay, err)
+				if sleep(ctx, delay) == nil {
+					continue
+				}
+			}
+			if self.Logger != nil {
+				self.Logger.Error("stripe: request failed",
+					"method", method,
+					"path", redact(path),
+					"attempts", attempt,
+					"error", redact(err.Error()))
+			}
+			return &ConnectionError{Err: err, Attempts: attempt}
+		}
 
-	// Log response if logging enabled
-	if _log {
-		fmt.Println("RESPONSE: ", r.StatusCode)
-		fmt.Println(string(body))
-	}
+		if o.response != nil {
+			o.response.set(r, req.Header.Get("Idempotency-Key"))
+		}
 
-	// is this an error?
-	if r.StatusCode != 200 {
-		error := Error{}
-		json.Unmarshal(body, &error)
-		return &error
-	}
+		// Log response if logging enabled
+		if self.Logger != nil {
+			self.Logger.Debug("stripe: response",
+				"method", method,
+				"path", redact(path),
+				"status", r.StatusCode,
+				"body", redact(string(body)))
+		}
+
+		// is this an error?
+		if r.StatusCode != 200 {
+			if o.upload == nil && self.shouldRetry(req, r, attempt) {
+				delay := self.retryDelay(attempt, r)
+				self.logRetry(method, path, attempt, delay, fmt.Errorf("http status %d", r.StatusCode))
+				if sleep(ctx, delay) == nil {
+					continue
+				}
+			}
+			return newError(r, body, attempt)
+		}
 
-	//parse the JSON response into the response object
-	return json.Unmarshal(body, v)
+		//parse the JSON response into the response object
+		if v == nil {
+			return nil
+		}
+		return json.Unmarshal(body, v)
+	}
 }
 
 // Response to a Deletion request.
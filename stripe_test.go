@@ -0,0 +1,151 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestClientKeys will test that two Clients authenticate using their own API
+// keys and base URLs, independent of the default Client.
+func TestClientKeys(t *testing.T) {
+	keys := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, _, _ := r.BasicAuth()
+		keys <- key
+		w.Write([]byte(`{"id":"ch_1"}`))
+	}))
+	defer server.Close()
+
+	client1 := New("sk_test_1")
+	client1.Url = server.URL
+	client2 := New("sk_test_2")
+	client2.Url = server.URL
+
+	if _, err := client1.Charges.Retrieve("ch_1"); err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+	}
+	if key := <-keys; key != "sk_test_1" {
+		t.Errorf("Expected API key sk_test_1, got %s", key)
+	}
+
+	if _, err := client2.Charges.Retrieve("ch_1"); err != nil {
+		t.Errorf("Expected Charge, got Error %s", err.Error())
+	}
+	if key := <-keys; key != "sk_test_2" {
+		t.Errorf("Expected API key sk_test_2, got %s", key)
+	}
+}
+
+// TestClientContext will test that a cancelled context aborts the outgoing
+// request instead of waiting for the Stripe response.
+func TestClientContext(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Charges.RetrieveContext(ctx, "ch_1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Error %s, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// TestCall will test that an arbitrary endpoint can be reached, and that the
+// response is decoded into the given value.
+func TestCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/transfers" || r.FormValue("amount") != "400" {
+			t.Errorf("Expected POST /v1/transfers amount=400, got %s %s %v", r.Method, r.URL.Path, r.Form)
+		}
+		w.Write([]byte(`{"id":"tr_1","amount":400}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+
+	transfer := struct {
+		Id     string `json:"id"`
+		Amount int64  `json:"amount"`
+	}{}
+	err := client.Call("POST", "/v1/transfers", url.Values{"amount": {"400"}}, &transfer)
+	if err != nil {
+		t.Errorf("Expected Transfer, got Error %s", err.Error())
+	}
+	if transfer.Id != "tr_1" || transfer.Amount != 400 {
+		t.Errorf("Expected Transfer tr_1 for 400, got %+v", transfer)
+	}
+}
+
+// TestVersionDecoding will test that responses from old and new Stripe API
+// versions decode into the same fields.
+func TestVersionDecoding(t *testing.T) {
+	older := Customer{}
+	json.Unmarshal([]byte(`{"id":"cus_1","default_card":"card_1",`+
+		`"subscription":{"status":"active"},`+
+		`"cards":{"object":"list","count":1,"data":[{"id":"card_1"}]}}`), &older)
+	newer := Customer{}
+	json.Unmarshal([]byte(`{"id":"cus_1","default_source":"card_1",`+
+		`"subscriptions":{"object":"list","data":[{"status":"active"}]},`+
+		`"sources":{"object":"list","data":[{"id":"card_1"}]}}`), &newer)
+
+	for _, cust := range []Customer{older, newer} {
+		if cust.Subscription == nil || cust.Subscription.Status != SubscriptionActive {
+			t.Errorf("Expected active Subscription, got %+v", cust.Subscription)
+		}
+		if len(cust.Subscriptions.Data) != 1 {
+			t.Errorf("Expected 1 Subscription in list, got %d", len(cust.Subscriptions.Data))
+		}
+		if len(cust.Cards.Data) != 1 || len(cust.Sources.Data) != 1 {
+			t.Errorf("Expected 1 Card and Source, got %d and %d", len(cust.Cards.Data), len(cust.Sources.Data))
+		}
+		if cust.DefaultCard.Id != "card_1" || cust.DefaultSource.Id != "card_1" {
+			t.Errorf("Expected default Card card_1, got %s and %s", cust.DefaultCard.Id, cust.DefaultSource.Id)
+		}
+	}
+
+	charge := Charge{}
+	json.Unmarshal([]byte(`{"id":"ch_1","statement_descriptor":"RUNCLUB"}`), &charge)
+	if charge.StatementDescription != "RUNCLUB" {
+		t.Errorf("Expected StatementDescription RUNCLUB, got %s", charge.StatementDescription)
+	}
+}
+
+// TestVersionParams will test that parameters renamed in newer Stripe API
+// versions are sent using the name of the version in use.
+func TestVersionParams(t *testing.T) {
+	form := url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"id":"ch_1"}`))
+	}))
+	defer server.Close()
+
+	client := New("sk_test_1")
+	client.Url = server.URL
+	params := ChargeParams{Amount: 400, Currency: USD, Token: "tok_1", StatementDescription: "5K RACE"}
+
+	client.Charges.Create(&params)
+	if form.Get("statement_description") != "5K RACE" {
+		t.Errorf("Expected statement_description, got %v", form)
+	}
+	client.Charges.Create(&params, APIVersion("2015-02-18"))
+	if form.Get("statement_descriptor") != "5K RACE" {
+		t.Errorf("Expected statement_descriptor, got %v", form)
+	}
+}
@@ -0,0 +1,141 @@
+// Package stripetest provides the test card numbers documented by Stripe, by
+// brand and by outcome, and a generator of random Luhn-valid card numbers, for
+// testing code that uses the stripe package.
+//
+// see https://stripe.com/docs/testing
+package stripetest
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/drone/go.stripe"
+)
+
+// Card is a test card number documented by Stripe.
+type Card struct {
+	// The card number.
+	Number string
+
+	// The brand of the card, one of the stripe Credit Card Type constants.
+	Brand string
+
+	// The error code (ie stripe.ErrCodeCardDeclined) returned when the card
+	// is charged, or an empty string if the charge succeeds.
+	Code string
+}
+
+// Params returns the params for creating the card, with an expiration date
+// one year from now and a security code of the right length for its brand.
+func (self Card) Params() *stripe.CardParams {
+	cvc := "123"
+	if stripe.GetCardBrand(self.Number).CVCLength == 4 {
+		cvc = "1234"
+	}
+	return &stripe.CardParams{
+		Number:   self.Number,
+		ExpMonth: 12,
+		ExpYear:  time.Now().Year() + 1,
+		CVC:      cvc,
+	}
+}
+
+// Cards that are charged successfully.
+var Cards = []Card{
+	{"4242424242424242", stripe.Visa, ""},
+	{"4012888888881881", stripe.Visa, ""},
+	{"4000056655665556", stripe.Visa, ""}, // debit
+	{"5555555555554444", stripe.MasterCard, ""},
+	{"2223003122003222", stripe.MasterCard, ""}, // 2-series
+	{"5200828282828210", stripe.MasterCard, ""}, // debit
+	{"5105105105105100", stripe.MasterCard, ""}, // prepaid
+	{"378282246310005", stripe.AmericanExpress, ""},
+	{"371449635398431", stripe.AmericanExpress, ""},
+	{"6011111111111117", stripe.Discover, ""},
+	{"6011000990139424", stripe.Discover, ""},
+	{"30569309025904", stripe.DinersClub, ""},
+	{"38520000023237", stripe.DinersClub, ""},
+	{"3566002020360505", stripe.JCB, ""},
+	{"6200000000000005", stripe.UnionPay, ""},
+}
+
+// Cards whose charges fail with an error code.
+var (
+	Declined        = Card{"4000000000000002", stripe.Visa, stripe.ErrCodeCardDeclined}
+	IncorrectCVC    = Card{"4000000000000127", stripe.Visa, stripe.ErrCodeIncorrectCVC}
+	ExpiredCard     = Card{"4000000000000069", stripe.Visa, stripe.ErrCodeExpiredCard}
+	ProcessingError = Card{"4000000000000119", stripe.Visa, stripe.ErrCodeProcessingError}
+	IncorrectNumber = Card{"4242424242424241", stripe.Visa, stripe.ErrCodeIncorrectNumber}
+)
+
+// Failures lists the cards whose charges fail with an error code.
+var Failures = []Card{
+	Declined,
+	IncorrectCVC,
+	ExpiredCard,
+	ProcessingError,
+	IncorrectNumber,
+}
+
+// ByBrand returns the cards of the given brand (ie stripe.Visa) that are
+// charged successfully.
+func ByBrand(brand string) []Card {
+	cards := []Card{}
+	for _, card := range Cards {
+		if card.Brand == brand {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// ByCode returns the card whose charges fail with the given error code (ie
+// stripe.ErrCodeExpiredCard), or false if there is none.
+func ByCode(code string) (Card, bool) {
+	for _, card := range Failures {
+		if card.Code == code {
+			return card, true
+		}
+	}
+	return Card{}, false
+}
+
+// Number generates a random Luhn-valid card number of the given length,
+// starting with the given prefix, ie Number("34", 15) for an American Express
+// card number. It panics if the prefix is not made of digits, or is not
+// shorter than the length.
+func Number(prefix string, length int) string {
+	if _, err := strconv.ParseUint(prefix, 10, 64); err != nil && prefix != "" {
+		panic("stripetest: invalid card number prefix " + prefix)
+	}
+	if len(prefix) >= length {
+		panic("stripetest: card number prefix " + prefix + " is too long")
+	}
+
+	digits := []byte(prefix)
+	for len(digits) < length-1 {
+		digits = append(digits, byte('0'+rand.Intn(10)))
+	}
+	return string(append(digits, checkDigit(digits)))
+}
+
+// checkDigit returns the Luhn check digit to append to the digits.
+//
+// see http://en.wikipedia.org/wiki/Luhn_algorithm
+func checkDigit(digits []byte) byte {
+	sum := 0
+	// once the check digit is appended, every other digit starting with the
+	// last of these digits is doubled.
+	for i, double := len(digits)-1, true; i >= 0; i, double = i-1, !double {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,80 @@
+package stripetest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/go.stripe"
+)
+
+// TestCards will test that every test card is Luhn-valid, except for the
+// incorrect number, and is recognized as its brand.
+func TestCards(t *testing.T) {
+	for _, card := range append(Cards, Failures...) {
+		valid, err := stripe.IsLuhnValid(card.Number)
+		if err != nil || valid != (card != IncorrectNumber) {
+			t.Errorf("Expected card %s valid %v, got %v", card.Number, card != IncorrectNumber, valid)
+		}
+		if got := stripe.GetCardType(card.Number); got != card.Brand {
+			t.Errorf("Expected card %s brand %s, got %s", card.Number, card.Brand, got)
+		}
+		if err := card.Params().Validate(); err != nil && valid {
+			t.Errorf("Expected valid params for card %s, got Error %s", card.Number, err.Error())
+		}
+	}
+}
+
+// TestByCode will test that a card is found for each failure error code.
+func TestByCode(t *testing.T) {
+	codes := []string{
+		stripe.ErrCodeCardDeclined,
+		stripe.ErrCodeIncorrectCVC,
+		stripe.ErrCodeExpiredCard,
+		stripe.ErrCodeProcessingError,
+	}
+	for _, code := range codes {
+		if card, ok := ByCode(code); !ok || card.Code != code {
+			t.Errorf("Expected card for code %s, got %v", code, card)
+		}
+	}
+	if _, ok := ByCode(stripe.ErrCodeMissing); ok {
+		t.Errorf("Expected no card for code %s", stripe.ErrCodeMissing)
+	}
+	if cards := ByBrand(stripe.AmericanExpress); len(cards) != 2 {
+		t.Errorf("Expected 2 American Express cards, got %d", len(cards))
+	}
+}
+
+// TestNumber will test that generated card numbers are Luhn-valid, have the
+// given length and prefix, and are recognized as the brand of the prefix.
+func TestNumber(t *testing.T) {
+	brands := []struct {
+		prefix string
+		length int
+		brand  string
+	}{
+		{"4", 16, stripe.Visa},
+		{"34", 15, stripe.AmericanExpress},
+		{"2221", 16, stripe.MasterCard},
+		{"51", 16, stripe.MasterCard},
+		{"622126", 16, stripe.Discover},
+		{"3528", 16, stripe.JCB},
+		{"36", 14, stripe.DinersClub},
+		{"620", 19, stripe.UnionPay},
+		{"", 12, ""},
+	}
+	for _, test := range brands {
+		for i := 0; i < 100; i++ {
+			number := Number(test.prefix, test.length)
+			if len(number) != test.length || !strings.HasPrefix(number, test.prefix) {
+				t.Errorf("Expected %d digit number starting with %s, got %s", test.length, test.prefix, number)
+			}
+			if valid, err := stripe.IsLuhnValid(number); !valid || err != nil {
+				t.Errorf("Expected Luhn-valid number, got %s", number)
+			}
+			if test.brand != "" && stripe.GetCardType(number) != test.brand {
+				t.Errorf("Expected %s number, got %s for %s", test.brand, stripe.GetCardType(number), number)
+			}
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package stripe
 
 import (
+	"context"
 	"net/url"
-	"strconv"
 )
 
 // Subscription Statuses
@@ -29,72 +29,73 @@ type Subscription struct {
 	TrialEnd           Int64  `json:"trial_end"`
 	CanceledAt         Int64  `json:"canceled_at"`
 	CancelAtPeriodEnd  bool   `json:"cancel_at_period_end"`
-	Quantity           int64  `json"quantity"`
+	Quantity           int64  `json:"quantity"`
 }
 
 // SubscriptionClient encapsulates operations for updating and canceling
 // customer subscriptions using the Stripe REST API.
-type SubscriptionClient struct{}
+type SubscriptionClient struct {
+	client *Client
+}
 
 // SubscriptionParams encapsulates options for updating a Customer's
 // subscription.
 type SubscriptionParams struct {
 	// The identifier of the plan to subscribe the customer to.
-	Plan string
+	Plan string `form:"plan"`
 
 	// (Optional) The code of the coupon to apply to the customer if you would
 	// like to apply it at the same time as creating the subscription.
-	Coupon string
+	Coupon string `form:"coupon,omitempty"`
 
 	// (Optional) Flag telling us whether to prorate switching plans during a
-	// billing cycle
-	Prorate bool
+	// billing cycle. Defaults to true.
+	Prorate Optional[bool] `form:"prorate"`
 
 	// (Optional) UTC integer timestamp representing the end of the trial period
 	// the customer will get before being charged for the first time. If set,
 	// trial_end will override the default trial period of the plan the customer
 	// is being subscribed to.
-	TrialEnd int64
+	TrialEnd int64 `form:"trial_end,omitempty"`
 
 	// (Optional) A new card to attach to the customer.
-	Card *CardParams
+	Card *CardParams `form:"card,omitempty"`
 
 	// (Optional) A new card Token to attach to the customer.
-	Token string
+	Token string `form:"card,omitempty"`
 
 	// (Optional) The quantity you'd like to apply to the subscription you're creating.
-	Quantity int64
+	Quantity Optional[int64] `form:"quantity"`
+}
+
+// appendTo encodes the params. The card parameter holds either a Card or a
+// Token, so a Token takes precedence over a Card.
+func (self *SubscriptionParams) appendTo(values *url.Values) {
+	if self == nil {
+		return
+	}
+	params := *self
+	if params.Token != "" {
+		params.Card = nil
+	}
+	appendParamsToValues(&params, values)
 }
 
 // Subscribes a customer to a new plan.
 //
 // see https://stripe.com/docs/api#update_subscription
-func (self *SubscriptionClient) Update(customerId string, params *SubscriptionParams) (*Subscription, error) {
-	values := url.Values{"plan": {params.Plan}}
+func (self *SubscriptionClient) Update(customerId string, params *SubscriptionParams, opts ...RequestOption) (*Subscription, error) {
+	return self.UpdateContext(context.Background(), customerId, params, opts...)
+}
 
-	// set optional parameters
-	if len(params.Coupon) != 0 {
-		values.Add("coupon", params.Coupon)
-	}
-	if params.Prorate {
-		values.Add("prorate", "true")
-	}
-	if params.TrialEnd != 0 {
-		values.Add("trial_end", strconv.FormatInt(params.TrialEnd, 10))
-	}
-	if params.Quantity != 0 {
-		values.Add("quantity", strconv.FormatInt(params.Quantity, 10))
-	}
-	// attach a new card, if requested
-	if len(params.Token) != 0 {
-		values.Add("card", params.Token)
-	} else if params.Card != nil {
-		appendCardParamsToValues(params.Card, &values)
-	}
+// UpdateContext is the context-aware version of Update.
+func (self *SubscriptionClient) UpdateContext(ctx context.Context, customerId string, params *SubscriptionParams, opts ...RequestOption) (*Subscription, error) {
+	values := url.Values{}
+	params.appendTo(&values)
 
 	s := Subscription{}
 	path := "/v1/customers/" + url.QueryEscape(customerId) + "/subscription"
-	err := query("POST", path, values, &s)
+	err := self.client.query(ctx, "POST", path, values, &s, opts...)
 	return &s, err
 }
 
@@ -102,22 +103,32 @@ func (self *SubscriptionClient) Update(customerId string, params *SubscriptionPa
 // subscription immediately.
 //
 // see https://stripe.com/docs/api#cancel_subscription
-func (self *SubscriptionClient) Cancel(customerId string) (*Subscription, error) {
+func (self *SubscriptionClient) Cancel(customerId string, opts ...RequestOption) (*Subscription, error) {
+	return self.CancelContext(context.Background(), customerId, opts...)
+}
+
+// CancelContext is the context-aware version of Cancel.
+func (self *SubscriptionClient) CancelContext(ctx context.Context, customerId string, opts ...RequestOption) (*Subscription, error) {
 	s := Subscription{}
 	path := "/v1/customers/" + url.QueryEscape(customerId) + "/subscription"
-	err := query("DELETE", path, nil, &s)
+	err := self.client.query(ctx, "DELETE", path, nil, &s, opts...)
 	return &s, err
 }
 
 // Cancels the customer's subscription at the end of the billing period.
 //
 // see https://stripe.com/docs/api#cancel_subscription
-func (self *SubscriptionClient) CancelAtPeriodEnd(customerId string) (*Subscription, error) {
+func (self *SubscriptionClient) CancelAtPeriodEnd(customerId string, opts ...RequestOption) (*Subscription, error) {
+	return self.CancelAtPeriodEndContext(context.Background(), customerId, opts...)
+}
+
+// CancelAtPeriodEndContext is the context-aware version of CancelAtPeriodEnd.
+func (self *SubscriptionClient) CancelAtPeriodEndContext(ctx context.Context, customerId string, opts ...RequestOption) (*Subscription, error) {
 	values := url.Values{}
 	values.Add("at_period_end", "true")
 
 	s := Subscription{}
 	path := "/v1/customers/" + url.QueryEscape(customerId) + "/subscription"
-	err := query("DELETE", path, values, &s)
+	err := self.client.query(ctx, "DELETE", path, values, &s, opts...)
 	return &s, err
 }
@@ -25,9 +25,9 @@ var (
 	sub2 = SubscriptionParams{
 		Plan:     "plan1",
 		Coupon:   "test coupon 1",
-		Prorate:  true,
+		Prorate:  Value(true),
 		TrialEnd: time.Now().Unix() + 1000000,
-		Quantity: 5,
+		Quantity: Value[int64](5),
 		Card: &CardParams{
 			Name:     "George Costanza",
 			Number:   "4242424242424242",
@@ -82,8 +82,8 @@ func TestUpdateSubscriptionCard(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected Subscription, got error %s", err.Error())
 	}
-	if resp.Quantity != sub2.Quantity {
-		t.Errorf("Expected Quantity %d, got %d", sub2.Quantity, resp.Quantity)
+	if want, _ := sub2.Quantity.Get(); resp.Quantity != want {
+		t.Errorf("Expected Quantity %d, got %d", want, resp.Quantity)
 	}
 
 	// Check to see if the customer's card was added
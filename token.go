@@ -1,6 +1,7 @@
 package stripe
 
 import (
+	"context"
 	"net/url"
 )
 
@@ -21,12 +22,26 @@ type Token struct {
 
 // TokenClient encapsulates operations for creating and querying tokens using
 // the Stripe REST API.
-type TokenClient struct{}
+type TokenClient struct {
+	client *Client
+}
 
 // TokenParams encapsulates options for creating a new Card Token.
 type TokenParams struct {
 	//Currency string REMOVED! no longer part of the API
-	Card *CardParams
+	Card *CardParams `form:"card"`
+}
+
+// Validate checks the card, without making a request, and returns a
+// ValidationError listing every invalid parameter.
+func (self *TokenParams) Validate() error {
+	errs := fieldErrors{}
+	if self.Card == nil {
+		errs.add("card", "is required")
+	} else {
+		errs.nested("card", self.Card.Validate())
+	}
+	return errs.err()
 }
 
 // Creates a single use token that wraps the details of a credit card.
@@ -35,21 +50,34 @@ type TokenParams struct {
 // attaching them to a customer.
 //
 // see https://stripe.com/docs/api#create_token
-func (self *TokenClient) Create(params *TokenParams) (*Token, error) {
+func (self *TokenClient) Create(params *TokenParams, opts ...RequestOption) (*Token, error) {
+	return self.CreateContext(context.Background(), params, opts...)
+}
+
+// CreateContext is the context-aware version of Create.
+func (self *TokenClient) CreateContext(ctx context.Context, params *TokenParams, opts ...RequestOption) (*Token, error) {
 	token := Token{}
-	values := url.Values{} // REMOVED "currency": {params.Currency}}
-	appendCardParamsToValues(params.Card, &values)
+	if err := self.client.validate(params); err != nil {
+		return &token, err
+	}
+	values := url.Values{}
+	appendParamsToValues(params, &values)
 
-	err := query("POST", "/v1/tokens", values, &token)
+	err := self.client.query(ctx, "POST", "/v1/tokens", values, &token, opts...)
 	return &token, err
 }
 
 // Retrieves the card token with the given Id.
 //
 // see https://stripe.com/docs/api#retrieve_token
-func (self *TokenClient) Retrieve(id string) (*Token, error) {
+func (self *TokenClient) Retrieve(id string, opts ...RequestOption) (*Token, error) {
+	return self.RetrieveContext(context.Background(), id, opts...)
+}
+
+// RetrieveContext is the context-aware version of Retrieve.
+func (self *TokenClient) RetrieveContext(ctx context.Context, id string, opts ...RequestOption) (*Token, error) {
 	token := Token{}
 	path := "/v1/tokens/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &token)
+	err := self.client.query(ctx, "GET", path, nil, &token, opts...)
 	return &token, err
 }
@@ -0,0 +1,102 @@
+package stripe
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a single invalid parameter, using the name of the
+// parameter as it is sent to Stripe, ie card[exp_month].
+type FieldError struct {
+	Param   string
+	Message string
+}
+
+func (self *FieldError) Error() string {
+	return self.Param + " " + self.Message
+}
+
+// ValidationError is returned by the Validate method of a Params struct, and
+// lists every invalid parameter. A request that fails validation is never
+// sent to Stripe.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (self *ValidationError) Error() string {
+	msgs := make([]string, 0, len(self.Fields))
+	for _, field := range self.Fields {
+		msgs = append(msgs, field.Error())
+	}
+	return "stripe: invalid parameters: " + strings.Join(msgs, "; ")
+}
+
+// validator is implemented by Params that can be validated before they are
+// sent to Stripe.
+type validator interface {
+	Validate() error
+}
+
+// validate validates the params before they are sent to Stripe, when the
+// Client is configured to do so.
+func (self *Client) validate(params validator) error {
+	if self == nil {
+		self = defaultClient
+	}
+	if !self.ValidateParams || reflect.ValueOf(params).IsNil() {
+		return nil
+	}
+	return params.Validate()
+}
+
+// fieldErrors collects the invalid parameters of a Params struct.
+type fieldErrors []*FieldError
+
+func (self *fieldErrors) add(param, msg string) {
+	*self = append(*self, &FieldError{Param: param, Message: msg})
+}
+
+// nested collects the invalid parameters of a nested Params struct, ie the
+// card of a Charge, prefixing each parameter with the given name.
+func (self *fieldErrors) nested(name string, err error) {
+	if invalid, ok := err.(*ValidationError); ok {
+		for _, field := range invalid.Fields {
+			param, rest, _ := strings.Cut(field.Param, "[")
+			if rest != "" {
+				rest = "[" + rest
+			}
+			self.add(name+"["+param+"]"+rest, field.Message)
+		}
+	}
+}
+
+// err returns a ValidationError if any of the parameters were invalid.
+func (self fieldErrors) err() error {
+	if len(self) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: self}
+}
+
+// isCurrency returns true if the currency is a 3-letter ISO code.
+func isCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits returns true if the string is made of digits only.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,109 @@
+package stripe
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestValidateCharge will test that every invalid parameter of a Charge,
+// including its card, is reported.
+func TestValidateCharge(t *testing.T) {
+	params := ChargeParams{
+		Amount:   25,
+		Currency: "dollars",
+		Card: &CardParams{
+			Number:   "4242424242424241",
+			ExpMonth: 1,
+			ExpYear:  time.Now().Year() - 1,
+			CVC:      "12",
+		},
+		StatementDescription: `"Pastrami" on Rye`,
+	}
+
+	err := params.Validate()
+	invalid, ok := err.(*ValidationError)
+	if !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+		return
+	}
+
+	want := []string{
+		"amount",
+		"currency",
+		"card[number]",
+		"card[exp_year]",
+		"card[cvc]",
+		"statement_description",
+		"statement_description",
+	}
+	if len(invalid.Fields) != len(want) {
+		t.Errorf("Expected %d invalid params, got %s", len(want), err.Error())
+		return
+	}
+	for i, field := range invalid.Fields {
+		if field.Param != want[i] {
+			t.Errorf("Expected invalid param %s, got %s", want[i], field.Param)
+		}
+	}
+
+	if err := charge1.Validate(); err != nil {
+		t.Errorf("Expected valid Charge, got Error %s", err.Error())
+	}
+}
+
+// TestValidateCoupon will test the percent off and duration rules of a
+// Coupon.
+func TestValidateCoupon(t *testing.T) {
+	tests := []struct {
+		params CouponParams
+		valid  bool
+	}{
+		{CouponParams{PercentOff: 5, Duration: DurationOnce}, true},
+		{CouponParams{PercentOff: 5, Duration: DurationRepeating, DurationInMonths: 3}, true},
+		{CouponParams{PercentOff: 0, Duration: DurationOnce}, false},
+		{CouponParams{PercentOff: 101, Duration: DurationOnce}, false},
+		{CouponParams{PercentOff: 5, Duration: DurationRepeating}, false},
+		{CouponParams{PercentOff: 5, Duration: DurationForever, DurationInMonths: 3}, false},
+		{CouponParams{PercentOff: 5, Duration: "always"}, false},
+	}
+	for _, test := range tests {
+		if err := test.params.Validate(); (err == nil) != test.valid {
+			t.Errorf("Expected Coupon %+v valid %v, got %v", test.params, test.valid, err)
+		}
+	}
+}
+
+// TestValidateParams will test that invalid params are never sent to Stripe
+// when the Client validates params.
+func TestValidateParams(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"id":"gold"}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server)
+	client.ValidateParams = true
+
+	params := PlanParams{Id: "gold", Amount: 999, Currency: USD, Interval: "fortnight", Name: "Gold"}
+	_, err := client.Plans.Create(&params)
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || invalid.Fields[0].Param != "interval" {
+		t.Errorf("Expected ValidationError for interval, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+
+	params.Interval = IntervalMonth
+	if _, err := client.Plans.Create(&params); err != nil {
+		t.Errorf("Expected Plan, got Error %s", err.Error())
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}